@@ -0,0 +1,272 @@
+package dimacs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ReadCNFWithComments is like ReadCNF but also returns the comment lines of
+// the file (including their "c" prefix) in order of appearance.
+func ReadCNFWithComments(r io.Reader) (CNFFormula, []string, error) {
+	b := CollectBuilder{CollectComments: true, Comments: []string{}}
+	lines, err := readBuilder(context.Background(), r, &b, ReadOptions{})
+	if err != nil {
+		return CNFFormula{}, nil, err
+	}
+	f, err := b.Formula()
+	if err != nil {
+		return CNFFormula{}, nil, newParseError(lines, "", err)
+	}
+	return f, b.Comments, nil
+}
+
+// ReadAll reads everything a DIMACS CNF file holds in a single call: the
+// formula, validated exactly as by ReadCNF, and all its comment lines in order
+// of appearance, including those following a clause on the same line (e.g.
+// "1 -2 0 c note") or the "%" end marker. It is equivalent to
+// ReadCNFWithComments.
+func ReadAll(r io.Reader) (CNFFormula, []string, error) {
+	return ReadCNFWithComments(r)
+}
+
+// ForEachClause reads a DIMACS CNF file from r and calls fn for each clause in
+// order, without storing the clauses. Reading stops as soon as fn returns stop
+// set to true, in which case ForEachClause returns nil without reading the
+// rest of the input, or a non-nil error, which is then returned as a
+// *ParseError wrapping it.
+//
+// The slice passed to fn is the reader's shared buffer: it is overwritten by
+// the next clause and must be copied to be retained. The problem line must
+// come before the clauses and declare a "cnf" problem but, as the input may
+// not be read entirely, the declared counts are not validated.
+func ForEachClause(r io.Reader, fn func(lits []int) (stop bool, err error)) error {
+	b := forEachBuilder{fn: fn}
+	_, err := readBuilder(context.Background(), r, &b, ReadOptions{})
+	if b.stopped {
+		return nil
+	}
+	return err
+}
+
+type forEachBuilder struct {
+	fn         func([]int) (bool, error)
+	hasProblem bool
+	stopped    bool
+}
+
+// errStopped is returned by forEachBuilder to stop the reading early.
+var errStopped = errors.New("stopped")
+
+func (b *forEachBuilder) Problem(p string, _ int, _ int) error {
+	if b.hasProblem {
+		return ErrDuplicateProblem
+	}
+	if p != "cnf" {
+		return fmt.Errorf("%w: expected \"cnf\" problem, got %q", ErrInvalidProblemType, p)
+	}
+	b.hasProblem = true
+	return nil
+}
+
+func (b *forEachBuilder) Clause(tmp []int) error {
+	if !b.hasProblem {
+		return ErrClauseBeforeProblem
+	}
+	stop, err := b.fn(tmp)
+	if err != nil {
+		return err
+	}
+	if stop {
+		b.stopped = true
+		return errStopped
+	}
+	return nil
+}
+
+func (b *forEachBuilder) Comment(_ string) error { return nil } // ignore comments
+
+// CollectBuilder is a Builder that collects the problem and the clauses of a
+// DIMACS CNF file into a CNFFormula, validating them as ReadCNF does. Unlike
+// custom builders that store tmpClause directly, it copies each clause into
+// its own slice so that the collected clauses are safe to retain.
+//
+// The zero value is ready to use:
+//
+//	b := &dimacs.CollectBuilder{CollectComments: true}
+//	if err := dimacs.ReadBuilder(r, b); err != nil {
+//		return err
+//	}
+//	f, err := b.Formula()
+type CollectBuilder struct {
+	cnfBuilder
+
+	// CollectComments enables the collection of the comment lines in
+	// Comments.
+	CollectComments bool
+
+	// Comments holds the comment lines (including their "c" prefix) in order
+	// of appearance if CollectComments is true.
+	Comments []string
+}
+
+// Comment collects the comment line if CollectComments is true.
+func (b *CollectBuilder) Comment(line string) error {
+	if b.CollectComments {
+		b.Comments = append(b.Comments, line)
+	}
+	return nil
+}
+
+// Formula returns the collected formula. It returns an error if no problem
+// line was found or if fewer clauses than declared were collected.
+func (b *CollectBuilder) Formula() (CNFFormula, error) {
+	return b.formula()
+}
+
+// CountingBuilder is a Builder that validates a DIMACS CNF file and computes
+// summary counts without storing its clauses, thus using constant memory. It
+// rejects the files that ReadCNF rejects as well as the clauses that contain a
+// variable greater than the declared number of variables. Since ReadBuilder
+// cannot detect missing clauses on its own, Finish must be called once the
+// whole file has been read:
+//
+//	b := &dimacs.CountingBuilder{}
+//	if err := dimacs.ReadBuilder(r, b); err != nil {
+//		return err
+//	}
+//	if err := b.Finish(); err != nil {
+//		return err
+//	}
+//	fmt.Println(b.NumClauses, b.NumLiterals, b.MaxVar)
+type CountingBuilder struct {
+	// DeclaredVars and DeclaredClauses are the counts declared in the problem
+	// line.
+	DeclaredVars    int
+	DeclaredClauses int
+
+	// NumClauses and NumLiterals are the number of clauses and the total
+	// number of literals read so far.
+	NumClauses  int
+	NumLiterals int
+
+	// MaxVar is the largest variable read so far.
+	MaxVar int
+
+	hasProblem bool
+}
+
+// Problem validates the problem line and records its counts.
+func (b *CountingBuilder) Problem(p string, v int, c int) error {
+	if b.hasProblem {
+		return ErrDuplicateProblem
+	}
+	if p != "cnf" {
+		return fmt.Errorf("%w: expected \"cnf\" problem, got %q", ErrInvalidProblemType, p)
+	}
+	if v < 0 {
+		return fmt.Errorf("%w: number of variables must be non-negative, got: %d", ErrInvalidProblemLine, v)
+	}
+	if c < 0 {
+		return fmt.Errorf("%w: number of clauses must be non-negative, got: %d", ErrInvalidProblemLine, c)
+	}
+	b.DeclaredVars, b.DeclaredClauses = v, c
+	b.hasProblem = true
+	return nil
+}
+
+// Clause validates the clause against the problem line and updates the
+// counts.
+func (b *CountingBuilder) Clause(tmpClause []int) error {
+	if !b.hasProblem {
+		return ErrClauseBeforeProblem
+	}
+	if b.NumClauses == b.DeclaredClauses {
+		return &ClauseCountError{Declared: b.DeclaredClauses, Actual: b.NumClauses + 1, TooMany: true}
+	}
+	for _, l := range tmpClause {
+		v := abs(l)
+		if v > b.DeclaredVars {
+			return fmt.Errorf("%w: literal %d, expected variables in 1..%d", ErrVarOutOfRange, l, b.DeclaredVars)
+		}
+		if v > b.MaxVar {
+			b.MaxVar = v
+		}
+	}
+	b.NumClauses++
+	b.NumLiterals += len(tmpClause)
+	return nil
+}
+
+// Comment ignores the comment line.
+func (b *CountingBuilder) Comment(line string) error { return nil }
+
+// Finish returns an error if no problem line was read or if fewer clauses than
+// declared were read.
+func (b *CountingBuilder) Finish() error {
+	if !b.hasProblem {
+		return ErrNoProblemLine
+	}
+	if b.NumClauses < b.DeclaredClauses {
+		return &ClauseCountError{Declared: b.DeclaredClauses, Actual: b.NumClauses}
+	}
+	return nil
+}
+
+// ReadBuilderWithRemap is like ReadBuilder but renames the variables of the
+// file with remap before passing them to b (see RemapBuilder).
+func ReadBuilderWithRemap(r io.Reader, b Builder, remap func(v int) int) error {
+	return ReadBuilder(r, &RemapBuilder{Builder: b, Remap: remap})
+}
+
+// RemapBuilder wraps a Builder and renames the variables of each clause with
+// Remap before passing the clause to the wrapped Builder. Remap is applied to
+// the variable of each literal and the sign of the literal is preserved (e.g.
+// -3 becomes -Remap(3)). It must return positive variables.
+//
+// The number of variables declared in the problem line is replaced by the
+// largest variable that variables 1 to nVars are renamed to, which requires
+// calling Remap nVars times. Comment lines are forwarded unchanged.
+type RemapBuilder struct {
+	Builder
+
+	Remap func(v int) int
+
+	buf []int
+}
+
+// Problem forwards the problem line with the number of variables recomputed
+// from Remap.
+func (b *RemapBuilder) Problem(p string, nVars int, nClauses int) error {
+	m := nVars
+	if nVars > 0 {
+		m = 0
+		for v := 1; v <= nVars; v++ {
+			rv := b.Remap(v)
+			if rv <= 0 {
+				return fmt.Errorf("variable %d renamed to non-positive variable %d", v, rv)
+			}
+			if rv > m {
+				m = rv
+			}
+		}
+	}
+	return b.Builder.Problem(p, m, nClauses)
+}
+
+// Clause forwards the clause with its variables renamed.
+func (b *RemapBuilder) Clause(tmpClause []int) error {
+	b.buf = append(b.buf[:0], tmpClause...)
+	for i, l := range b.buf {
+		v := b.Remap(abs(l))
+		if v <= 0 {
+			return fmt.Errorf("variable %d renamed to non-positive variable %d", abs(l), v)
+		}
+		if l < 0 {
+			v = -v
+		}
+		b.buf[i] = v
+	}
+	return b.Builder.Clause(b.buf)
+}
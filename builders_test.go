@@ -0,0 +1,563 @@
+package dimacs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCollectBuilder(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		input        string
+		collect      bool
+		wantCNF      CNFFormula
+		wantComments []string
+		wantErr      bool
+	}{
+		{
+			desc:    "without comments",
+			input:   validCNF_manyComments,
+			collect: false,
+			wantCNF: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{1, 2, 3}, {1, -2, 3}, {1, -3}, {-2, -3}},
+			},
+			wantComments: nil,
+			wantErr:      false,
+		},
+		{
+			desc:    "with comments",
+			input:   validCNF_manyComments,
+			collect: true,
+			wantCNF: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{1, 2, 3}, {1, -2, 3}, {1, -3}, {-2, -3}},
+			},
+			wantComments: []string{
+				"c comment 1",
+				"c comment 2",
+				"c comment 3",
+				"c comment 4",
+				"c comment 5",
+			},
+			wantErr: false,
+		},
+		{
+			desc:         "missing problem line",
+			input:        "c comment",
+			collect:      true,
+			wantCNF:      CNFFormula{},
+			wantComments: []string{"c comment"},
+			wantErr:      true,
+		},
+		{
+			desc:         "missing clauses",
+			input:        "p cnf 3 2\n1 2 3 0\n",
+			collect:      true,
+			wantCNF:      CNFFormula{},
+			wantComments: nil,
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			b := &CollectBuilder{CollectComments: tc.collect}
+			if err := ReadBuilder(strings.NewReader(tc.input), b); err != nil {
+				t.Fatalf("ReadBuilder(): want no error, got %s", err)
+			}
+
+			gotCNF, gotErr := b.Formula()
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("Formula(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("Formula(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantCNF, gotCNF); diff != "" {
+				t.Errorf("Formula(): CNF mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantComments, b.Comments); diff != "" {
+				t.Errorf("CollectBuilder: comments mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCollectBuilder_clausesAreCopied(t *testing.T) {
+	b := &CollectBuilder{}
+	tmp := []int{1, 2}
+	if err := b.Problem("cnf", 2, 1); err != nil {
+		t.Fatalf("Problem(): want no error, got %s", err)
+	}
+	if err := b.Clause(tmp); err != nil {
+		t.Fatalf("Clause(): want no error, got %s", err)
+	}
+
+	tmp[0] = -1 // simulate the reuse of the shared buffer
+
+	f, err := b.Formula()
+	if err != nil {
+		t.Fatalf("Formula(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff([][]int{{1, 2}}, f.Clauses); diff != "" {
+		t.Errorf("Formula(): clauses mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadCNFWithComments(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		input        string
+		wantCNF      CNFFormula
+		wantComments []string
+		wantErr      bool
+	}{
+		{
+			desc:  "comments",
+			input: validCNF_manyComments,
+			wantCNF: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{1, 2, 3}, {1, -2, 3}, {1, -3}, {-2, -3}},
+			},
+			wantComments: []string{
+				"c comment 1",
+				"c comment 2",
+				"c comment 3",
+				"c comment 4",
+				"c comment 5",
+			},
+			wantErr: false,
+		},
+		{
+			desc:         "no comments",
+			input:        validCNF_noComments,
+			wantCNF:      testFormula,
+			wantComments: []string{},
+			wantErr:      false,
+		},
+		{
+			desc:         "missing clauses",
+			input:        "c comment\np cnf 3 2\n1 2 0\n",
+			wantCNF:      CNFFormula{},
+			wantComments: nil,
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotCNF, gotComments, gotErr := ReadCNFWithComments(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadCNFWithComments(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadCNFWithComments(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantCNF, gotCNF); diff != "" {
+				t.Errorf("ReadCNFWithComments(): CNF mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantComments, gotComments); diff != "" {
+				t.Errorf("ReadCNFWithComments(): comments mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	input := "c header\np cnf 3 2\n1 -2 0 c note\nc between\n-3 0\n%\n0\nc trailer\n"
+
+	gotCNF, gotComments, err := ReadAll(strings.NewReader(input))
+
+	if err != nil {
+		t.Fatalf("ReadAll(): want no error, got %s", err)
+	}
+	wantCNF := CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {-3}}}
+	if diff := cmp.Diff(wantCNF, gotCNF); diff != "" {
+		t.Errorf("ReadAll(): CNF mismatch (-want +got):\n%s", diff)
+	}
+	wantComments := []string{"c header", "c note", "c between", "c trailer"}
+	if diff := cmp.Diff(wantComments, gotComments); diff != "" {
+		t.Errorf("ReadAll(): comments mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadAll_validation(t *testing.T) {
+	inputs := []string{
+		"",
+		"c no problem line\n",
+		"p cnf 3 1\n1 2 3 0\n-1 0\n",
+		"p cnf 3 2\n1 2 3 0\n",
+		"p cnf 3 1\n1 0 3 0\n",
+		"p cnf 3 1\n1 x 0\n",
+		"1 0\np cnf 3 1\n",
+		"p cnf 3 1\np cnf 3 1\n1 0\n",
+		"p sat 3\n",
+		validCNF_manyComments,
+		validCNF_endOfFile,
+	}
+
+	for _, input := range inputs {
+		wantCNF, wantErr := ReadCNF(strings.NewReader(input))
+
+		gotCNF, _, gotErr := ReadAll(strings.NewReader(input))
+
+		if !errorEqual(gotErr, wantErr) {
+			t.Errorf("ReadAll(%q): want error %v, got %v", input, wantErr, gotErr)
+		}
+		if diff := cmp.Diff(wantCNF, gotCNF); diff != "" {
+			t.Errorf("ReadAll(%q): CNF mismatch (-want +got):\n%s", input, diff)
+		}
+	}
+}
+
+func TestCountingBuilder(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		input         string
+		want          CountingBuilder
+		wantReadErr   bool
+		wantFinishErr error
+	}{
+		{
+			desc:  "valid cnf",
+			input: validCNF_manyComments,
+			want: CountingBuilder{
+				DeclaredVars:    3,
+				DeclaredClauses: 4,
+				NumClauses:      4,
+				NumLiterals:     10,
+				MaxVar:          3,
+				hasProblem:      true,
+			},
+		},
+		{
+			desc:  "unused variables",
+			input: "p cnf 5 2\n1 -2 0\n0\n",
+			want: CountingBuilder{
+				DeclaredVars:    5,
+				DeclaredClauses: 2,
+				NumClauses:      2,
+				NumLiterals:     2,
+				MaxVar:          2,
+				hasProblem:      true,
+			},
+		},
+		{
+			desc:        "invalid problem type",
+			input:       "p sat 3 1\n",
+			wantReadErr: true,
+		},
+		{
+			desc:        "duplicate problem line",
+			input:       "p cnf 3 1\np cnf 3 1\n",
+			wantReadErr: true,
+		},
+		{
+			desc:        "clause before problem line",
+			input:       "1 0\np cnf 3 1\n",
+			wantReadErr: true,
+		},
+		{
+			desc:        "too many clauses",
+			input:       "p cnf 3 1\n1 0\n2 0\n",
+			wantReadErr: true,
+		},
+		{
+			desc:        "variable out of range",
+			input:       "p cnf 3 1\n1 -4 0\n",
+			wantReadErr: true,
+		},
+		{
+			desc:  "missing clauses",
+			input: "p cnf 3 2\n1 0\n",
+			want: CountingBuilder{
+				DeclaredVars:    3,
+				DeclaredClauses: 2,
+				NumClauses:      1,
+				NumLiterals:     1,
+				MaxVar:          1,
+				hasProblem:      true,
+			},
+			wantFinishErr: ErrMissingClauses,
+		},
+		{
+			desc:          "no problem line",
+			input:         "c comment\n",
+			want:          CountingBuilder{},
+			wantFinishErr: ErrNoProblemLine,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			b := &CountingBuilder{}
+
+			gotErr := ReadBuilder(strings.NewReader(tc.input), b)
+
+			if tc.wantReadErr {
+				if gotErr == nil {
+					t.Errorf("ReadBuilder(): want error, got nil")
+				}
+				return
+			}
+			if gotErr != nil {
+				t.Fatalf("ReadBuilder(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.want, *b, cmp.AllowUnexported(CountingBuilder{})); diff != "" {
+				t.Errorf("CountingBuilder: mismatch (-want +got):\n%s", diff)
+			}
+			if err := b.Finish(); !errors.Is(err, tc.wantFinishErr) {
+				t.Errorf("Finish(): want error %v, got %v", tc.wantFinishErr, err)
+			}
+		})
+	}
+}
+
+// scribbleBuilder wraps a Builder and overwrites each clause once the wrapped
+// Builder returns, as the reader does when reusing its buffer for the next
+// clause line. Clauses retained by the wrapped Builder are thus corrupted.
+type scribbleBuilder struct {
+	Builder
+}
+
+func (b *scribbleBuilder) Clause(tmpClause []int) error {
+	err := b.Builder.Clause(tmpClause)
+	for i := range tmpClause {
+		tmpClause[i] = 0
+	}
+	return err
+}
+
+func TestBuilders_noRetention(t *testing.T) {
+	input := randomCNF(50, 200, 3)
+	f, err := ReadCNF(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadCNF(): want no error, got %s", err)
+	}
+	deduped := f.Clone()
+	deduped.Dedup()
+	unique := f.Clone()
+	unique.Normalize(NormalizeOptions{RemoveDuplicateLiterals: true})
+
+	collect := &CollectBuilder{}
+	arena := &CollectBuilder{cnfBuilder: cnfBuilder{opts: ReadCNFOptions{Arena: true}}}
+	document := &documentBuilder{}
+	lenient := &CollectBuilder{cnfBuilder: cnfBuilder{opts: ReadCNFOptions{IgnoreClauseCount: true}}}
+	uniqueCollect := &CollectBuilder{}
+	testCases := []struct {
+		desc        string
+		builder     Builder
+		clauses     func() [][]int
+		wantClauses [][]int
+	}{
+		{
+			desc:        "CollectBuilder",
+			builder:     collect,
+			clauses:     func() [][]int { return collect.cnf.Clauses },
+			wantClauses: f.Clauses,
+		},
+		{
+			desc:        "CollectBuilder arena",
+			builder:     arena,
+			clauses:     func() [][]int { g, _ := arena.Formula(); return g.Clauses },
+			wantClauses: f.Clauses,
+		},
+		{
+			desc:        "documentBuilder",
+			builder:     document,
+			clauses:     func() [][]int { return document.doc.Formula().Clauses },
+			wantClauses: f.Clauses,
+		},
+		{
+			desc:        "DedupBuilder",
+			builder:     &DedupBuilder{Builder: lenient},
+			clauses:     func() [][]int { return lenient.cnf.Clauses },
+			wantClauses: deduped.Clauses,
+		},
+		{
+			desc:        "UniqueLiteralsBuilder",
+			builder:     &UniqueLiteralsBuilder{Builder: uniqueCollect},
+			clauses:     func() [][]int { return uniqueCollect.cnf.Clauses },
+			wantClauses: unique.Clauses,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := ReadBuilder(strings.NewReader(input), &scribbleBuilder{tc.builder}); err != nil {
+				t.Fatalf("ReadBuilder(): want no error, got %s", err)
+			}
+
+			if diff := cmp.Diff(tc.wantClauses, tc.clauses()); diff != "" {
+				t.Errorf("%s: clauses mismatch (-want +got):\n%s", tc.desc, diff)
+			}
+		})
+	}
+}
+
+func BenchmarkReadBuilder_sharedBuffer(b *testing.B) {
+	input := randomCNF(100000, 400000, 3)
+	builders := []struct {
+		desc string
+		new  func() Builder
+	}{
+		{desc: "discard", new: func() Builder { return &CountingBuilder{} }},
+		{desc: "collect", new: func() Builder { return &CollectBuilder{} }},
+	}
+	for _, bb := range builders {
+		b.Run(bb.desc, func(b *testing.B) {
+			b.SetBytes(int64(len(input)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := ReadBuilder(strings.NewReader(input), bb.new()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestReadBuilderWithRemap(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		remap   func(v int) int
+		want    CNFFormula
+		wantErr bool
+	}{
+		{
+			desc:  "identity",
+			input: "p cnf 3 2\n1 -2 0\n-3 0\n",
+			remap: func(v int) int { return v },
+			want:  CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {-3}}},
+		},
+		{
+			desc:  "offset",
+			input: "p cnf 3 2\n1 -2 0\n-3 0\n",
+			remap: func(v int) int { return v + 10 },
+			want:  CNFFormula{NumVars: 13, Clauses: [][]int{{11, -12}, {-13}}},
+		},
+		{
+			desc:  "permutation",
+			input: "p cnf 3 3\n1 -2 0\n-3 0\n0\n",
+			remap: func(v int) int { return map[int]int{1: 3, 2: 1, 3: 2}[v] },
+			want:  CNFFormula{NumVars: 3, Clauses: [][]int{{3, -1}, {-2}, {}}},
+		},
+		{
+			desc:    "non-positive variable in problem",
+			input:   "p cnf 2 1\n1 -2 0\n",
+			remap:   func(v int) int { return v - 1 },
+			wantErr: true,
+		},
+		{
+			desc:    "non-positive variable in clause",
+			input:   "p cnf 1 1\n1 -2 0\n",
+			remap:   func(v int) int { return 2 - v },
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			b := &CollectBuilder{}
+
+			gotErr := ReadBuilderWithRemap(strings.NewReader(tc.input), b, tc.remap)
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadBuilderWithRemap(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadBuilderWithRemap(): want no error, got %s", gotErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			got, err := b.Formula()
+			if err != nil {
+				t.Fatalf("Formula(): want no error, got %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ReadBuilderWithRemap(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestForEachClause(t *testing.T) {
+	fnErr := errors.New("fn error")
+	testCases := []struct {
+		desc      string
+		input     string
+		stopAfter int   // number of clauses after which fn stops, 0 to never stop
+		fnErr     error // error returned by fn on the first clause, if any
+		want      [][]int
+		wantErr   bool
+	}{
+		{
+			desc:  "all clauses",
+			input: "c comment\np cnf 3 3\n1 -2 0\n3 0\n0\n",
+			want:  [][]int{{1, -2}, {3}, {}},
+		},
+		{
+			desc:      "stop early",
+			input:     "p cnf 3 3\n1 -2 0\n3 0\n1 x 0\n",
+			stopAfter: 2,
+			want:      [][]int{{1, -2}, {3}},
+		},
+		{
+			desc:    "invalid clause",
+			input:   "p cnf 3 3\n1 -2 0\n1 x 0\n3 0\n",
+			want:    [][]int{{1, -2}},
+			wantErr: true,
+		},
+		{
+			desc:    "function error",
+			input:   "p cnf 3 2\n1 -2 0\n3 0\n",
+			fnErr:   fnErr,
+			want:    [][]int{{1, -2}},
+			wantErr: true,
+		},
+		{
+			desc:    "no problem line",
+			input:   "1 -2 0\n",
+			want:    [][]int{},
+			wantErr: true,
+		},
+		{
+			desc:    "invalid problem type",
+			input:   "p wcnf 3 2\n1 -2 0\n",
+			want:    [][]int{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := [][]int{}
+
+			gotErr := ForEachClause(strings.NewReader(tc.input), func(lits []int) (bool, error) {
+				got = append(got, append([]int{}, lits...))
+				return len(got) == tc.stopAfter, tc.fnErr
+			})
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ForEachClause(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ForEachClause(): want no error, got %s", gotErr)
+			}
+			if tc.fnErr != nil && !errors.Is(gotErr, tc.fnErr) {
+				t.Errorf("ForEachClause(): want error wrapping %s, got %v", tc.fnErr, gotErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ForEachClause(): clauses mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
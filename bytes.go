@@ -0,0 +1,20 @@
+package dimacs
+
+import "context"
+
+// ReadCNFBytes is like ReadCNF but parses the formula from data. Lines are
+// read directly from the slice, which avoids the copies made when reading from
+// an io.Reader.
+func ReadCNFBytes(data []byte) (CNFFormula, error) {
+	rd := Reader{}
+	rd.resetBytes(data)
+	return rd.readCNF(context.Background(), ReadCNFOptions{})
+}
+
+// ReadBuilderBytes is like ReadBuilder but reads the DIMACS file from data.
+func ReadBuilderBytes(data []byte, b Builder) error {
+	rd := Reader{}
+	rd.resetBytes(data)
+	_, err := rd.read(context.Background(), b, ReadOptions{})
+	return err
+}
@@ -0,0 +1,70 @@
+package dimacs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadCNFBytes(t *testing.T) {
+	inputs := []string{
+		"",
+		"c no problem or clause",
+		"p foo 3 4",
+		"p cnf 3 a",
+		"p cnf 3 4\np cnf 3 4",
+		"1 2 3 0\np cnf 3 4",
+		"p cnf 3 1\n1 2 3 0\n2 3 0",
+		"p cnf 3 2\n1 2 3 0",
+		"p cnf 3 1\n1 a 3 0",
+		"p cnf 3 1\n1 0 3 0",
+		"p cnf 3 1\n1 2 0 c trailing comment\n",
+		validCNF_noComments,
+		validCNF_manyComments,
+		validCNF_endOfFile,
+		validCNF_crlf,
+		validCNF_bom,
+		randomCNF(100, 400, 3),
+	}
+
+	for _, input := range inputs {
+		wantCNF, wantErr := ReadCNF(strings.NewReader(input))
+
+		gotCNF, gotErr := ReadCNFBytes([]byte(input))
+
+		if !errorEqual(gotErr, wantErr) {
+			t.Errorf("ReadCNFBytes(%.20q): want error %v, got %v", input, wantErr, gotErr)
+		}
+		if diff := cmp.Diff(wantCNF, gotCNF); diff != "" {
+			t.Errorf("ReadCNFBytes(%.20q): CNF mismatch (-want +got):\n%s", input, diff)
+		}
+	}
+}
+
+func TestReadBuilderBytes(t *testing.T) {
+	wantErr := errors.New("clause error")
+
+	gotErr := ReadBuilderBytes([]byte(validCNF_manyComments), &testBuilder{ClauseErr: wantErr})
+
+	var pe *ParseError
+	if !errors.As(gotErr, &pe) || pe.Line != 6 {
+		t.Errorf("ReadBuilderBytes(): want *ParseError at line 6, got %v", gotErr)
+	}
+	if !errors.Is(gotErr, wantErr) {
+		t.Errorf("ReadBuilderBytes(): want error %s, got %v", wantErr, gotErr)
+	}
+}
+
+func BenchmarkReadCNFBytes_large(b *testing.B) {
+	input := []byte(randomCNF(100000, 400000, 3))
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ReadCNFBytes(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package dimacs
+
+// Concat returns the formula made of the clauses of all the given formulas, in
+// order. Variables are shared across formulas: variable v denotes the same
+// variable in every input. NumVars is the largest variable appearing in the
+// clauses of the result (or 0 if there is none), which can be smaller than the
+// NumVars of the inputs.
+//
+// Clauses are copied so that the inputs are left untouched.
+func Concat(formulas ...CNFFormula) CNFFormula {
+	return concat(formulas, false)
+}
+
+// ConcatRenamed is like Concat but shifts the variables of each formula into a
+// fresh range that is disjoint from the variables of the other formulas. The
+// variables of the i-th formula are shifted by the sum of the NumVars of the
+// formulas before it (e.g. variable 1 of the second formula becomes variable
+// NumVars+1 of the first formula). As with Concat, NumVars is the largest
+// variable appearing in the clauses of the result.
+func ConcatRenamed(formulas ...CNFFormula) CNFFormula {
+	return concat(formulas, true)
+}
+
+// Append returns the disjoint union of f and other: the clauses of f followed
+// by the clauses of other with its variables shifted by f.NumVars (e.g.
+// literal -1 of other becomes -(f.NumVars+1)). Unlike ConcatRenamed, NumVars
+// is the sum of the NumVars of both formulas so that every model of the result
+// splits into a model of f and a model of other. As in ConcatRenamed, the
+// variables are shifted by the largest variable of f instead if f uses more
+// variables than it declares.
+//
+// Clauses are copied so that f and other are left untouched.
+func (f CNFFormula) Append(other CNFFormula) CNFFormula {
+	res := concat([]CNFFormula{f, other}, true)
+	if n := f.NumVars + other.NumVars; n > res.NumVars {
+		res.NumVars = n
+	}
+	return res
+}
+
+// Filter returns the formula made of the clauses of f for which keep returns
+// true, in order (e.g. to drop the unit clauses or to only keep the clauses
+// over a subset of the variables). NumVars is preserved even if some variables
+// no longer appear in the result; see MaxVar and Compact to shrink it.
+//
+// Clauses are copied so that the result is independent of f. The clause
+// passed to keep is the clause of f and must not be modified.
+func (f CNFFormula) Filter(keep func(clause []int) bool) CNFFormula {
+	res := CNFFormula{NumVars: f.NumVars, Clauses: [][]int{}}
+	for _, c := range f.Clauses {
+		if keep(c) {
+			res.Clauses = append(res.Clauses, append(make([]int, 0, len(c)), c...))
+		}
+	}
+	return res
+}
+
+func concat(formulas []CNFFormula, rename bool) CNFFormula {
+	n := 0
+	for _, f := range formulas {
+		n += len(f.Clauses)
+	}
+	res := CNFFormula{Clauses: make([][]int, 0, n)}
+
+	offset := 0
+	for _, f := range formulas {
+		for _, c := range f.Clauses {
+			nc := make([]int, len(c))
+			for i, l := range c {
+				if l < 0 {
+					nc[i] = l - offset
+				} else {
+					nc[i] = l + offset
+				}
+				if v := abs(nc[i]); v > res.NumVars {
+					res.NumVars = v
+				}
+			}
+			res.Clauses = append(res.Clauses, nc)
+		}
+		if rename {
+			if v := maxVar(f.Clauses); v > f.NumVars {
+				offset += v // do not overlap with undeclared variables
+			} else {
+				offset += f.NumVars
+			}
+		}
+	}
+	return res
+}
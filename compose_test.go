@@ -0,0 +1,247 @@
+package dimacs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConcat(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		formulas []CNFFormula
+		want     CNFFormula
+	}{
+		{
+			desc:     "no formulas",
+			formulas: nil,
+			want:     CNFFormula{Clauses: [][]int{}},
+		},
+		{
+			desc: "empty formulas",
+			formulas: []CNFFormula{
+				{NumVars: 2, Clauses: [][]int{}},
+				{},
+			},
+			want: CNFFormula{Clauses: [][]int{}},
+		},
+		{
+			desc: "shared variables",
+			formulas: []CNFFormula{
+				{NumVars: 2, Clauses: [][]int{{1, -2}}},
+				{NumVars: 3, Clauses: [][]int{{-1, 3}, {}}},
+			},
+			want: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{1, -2}, {-1, 3}, {}},
+			},
+		},
+		{
+			desc: "unused variables",
+			formulas: []CNFFormula{
+				{NumVars: 10, Clauses: [][]int{{1, -2}}},
+				{NumVars: 5, Clauses: [][]int{{-3}}},
+			},
+			want: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{1, -2}, {-3}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := Concat(tc.formulas...)
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Concat(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConcatRenamed(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		formulas []CNFFormula
+		want     CNFFormula
+	}{
+		{
+			desc:     "no formulas",
+			formulas: nil,
+			want:     CNFFormula{Clauses: [][]int{}},
+		},
+		{
+			desc: "disjoint variables",
+			formulas: []CNFFormula{
+				{NumVars: 2, Clauses: [][]int{{1, -2}}},
+				{NumVars: 3, Clauses: [][]int{{-1, 3}, {}}},
+				{NumVars: 1, Clauses: [][]int{{-1}}},
+			},
+			want: CNFFormula{
+				NumVars: 6,
+				Clauses: [][]int{{1, -2}, {-3, 5}, {}, {-6}},
+			},
+		},
+		{
+			desc: "unused variables",
+			formulas: []CNFFormula{
+				{NumVars: 10, Clauses: [][]int{{1, -2}}},
+				{NumVars: 5, Clauses: [][]int{{-3}}},
+			},
+			want: CNFFormula{
+				NumVars: 13,
+				Clauses: [][]int{{1, -2}, {-13}},
+			},
+		},
+		{
+			desc: "undeclared variables",
+			formulas: []CNFFormula{
+				{NumVars: 1, Clauses: [][]int{{1, -2}}},
+				{NumVars: 1, Clauses: [][]int{{1}}},
+			},
+			want: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{1, -2}, {3}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := ConcatRenamed(tc.formulas...)
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ConcatRenamed(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAppend(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		f     CNFFormula
+		other CNFFormula
+		want  CNFFormula
+	}{
+		{
+			desc:  "empty formulas",
+			f:     CNFFormula{},
+			other: CNFFormula{},
+			want:  CNFFormula{Clauses: [][]int{}},
+		},
+		{
+			desc:  "disjoint variables",
+			f:     CNFFormula{NumVars: 2, Clauses: [][]int{{1, -2}}},
+			other: CNFFormula{NumVars: 3, Clauses: [][]int{{-1, 3}, {}, {2}}},
+			want: CNFFormula{
+				NumVars: 5,
+				Clauses: [][]int{{1, -2}, {-3, 5}, {}, {4}},
+			},
+		},
+		{
+			desc:  "unused variables",
+			f:     CNFFormula{NumVars: 4, Clauses: [][]int{{1}}},
+			other: CNFFormula{NumVars: 3, Clauses: [][]int{{-1}}},
+			want: CNFFormula{
+				NumVars: 7,
+				Clauses: [][]int{{1}, {-5}},
+			},
+		},
+		{
+			desc:  "under-declared variables",
+			f:     CNFFormula{NumVars: 1, Clauses: [][]int{{1, -2}}},
+			other: CNFFormula{NumVars: 1, Clauses: [][]int{{-1, 2}}},
+			want: CNFFormula{
+				NumVars: 4,
+				Clauses: [][]int{{1, -2}, {-3, 4}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tc.f.Append(tc.other)
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Append(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	f := CNFFormula{
+		NumVars: 4,
+		Clauses: [][]int{{1, -2}, {3}, {}, {-4, 2, 1}, {-1}},
+	}
+	testCases := []struct {
+		desc string
+		keep func([]int) bool
+		want CNFFormula
+	}{
+		{
+			desc: "keep all",
+			keep: func([]int) bool { return true },
+			want: f,
+		},
+		{
+			desc: "keep none",
+			keep: func([]int) bool { return false },
+			want: CNFFormula{NumVars: 4, Clauses: [][]int{}},
+		},
+		{
+			desc: "drop unit clauses",
+			keep: func(c []int) bool { return len(c) != 1 },
+			want: CNFFormula{NumVars: 4, Clauses: [][]int{{1, -2}, {}, {-4, 2, 1}}},
+		},
+		{
+			desc: "variable subset",
+			keep: func(c []int) bool {
+				for _, l := range c {
+					if abs(l) > 2 {
+						return false
+					}
+				}
+				return true
+			},
+			want: CNFFormula{NumVars: 4, Clauses: [][]int{{1, -2}, {}, {-1}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := f.Filter(tc.keep)
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Filter(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFilter_deepCopy(t *testing.T) {
+	f := CNFFormula{NumVars: 2, Clauses: [][]int{{1, -2}, {2}}}
+
+	got := f.Filter(func([]int) bool { return true })
+	got.Clauses[0][0] = -1
+	got.Clauses[1] = append(got.Clauses[1], 1)
+
+	want := CNFFormula{NumVars: 2, Clauses: [][]int{{1, -2}, {2}}}
+	if diff := cmp.Diff(want, f); diff != "" {
+		t.Errorf("Filter(): input modified (-want +got):\n%s", diff)
+	}
+}
+
+func TestConcat_deepCopy(t *testing.T) {
+	f := CNFFormula{NumVars: 2, Clauses: [][]int{{1, -2}}}
+
+	got := ConcatRenamed(f, f)
+	got.Clauses[0][0] = 42
+
+	want := CNFFormula{NumVars: 2, Clauses: [][]int{{1, -2}}}
+	if diff := cmp.Diff(want, f); diff != "" {
+		t.Errorf("ConcatRenamed(): input modified (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,58 @@
+package dimacs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// cancelBuilder cancels its context after having processed a given number of
+// clauses.
+type cancelBuilder struct {
+	testBuilder
+	cancel  context.CancelFunc
+	after   int
+	clauses int
+}
+
+func (cb *cancelBuilder) Clause(_ []int) error {
+	cb.clauses++
+	if cb.clauses == cb.after {
+		cb.cancel()
+	}
+	return nil
+}
+
+func TestReadBuilderContext_cancelled(t *testing.T) {
+	input := "p cnf 1 10000\n" + strings.Repeat("1 0\n", 10000)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cb := &cancelBuilder{cancel: cancel, after: 10}
+
+	gotErr := ReadBuilderContext(ctx, strings.NewReader(input), cb)
+
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Errorf("ReadBuilderContext(): want error %s, got %v", context.Canceled, gotErr)
+	}
+	if cb.clauses >= 10000 {
+		t.Errorf("ReadBuilderContext(): want parsing to stop early, got %d clauses", cb.clauses)
+	}
+}
+
+func TestReadCNFContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, gotErr := ReadCNFContext(ctx, strings.NewReader(validCNF_noComments))
+
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Errorf("ReadCNFContext(): want error %s, got %v", context.Canceled, gotErr)
+	}
+
+	_, gotErr = ReadCNFContext(context.Background(), strings.NewReader(validCNF_noComments))
+
+	if gotErr != nil {
+		t.Errorf("ReadCNFContext(): want no error, got %s", gotErr)
+	}
+}
@@ -0,0 +1,51 @@
+package dimacs
+
+import (
+	"bytes"
+	"io"
+)
+
+// ReadCubes reads a file of cubes, such as the assumptions given to an
+// incremental solver alongside a base formula. Each line is a cube made of
+// literals terminated by a 0 (e.g. "1 -3 0"), optionally prefixed by "a" as
+// in the assumption lines of ICNF files. The terminating 0 is not part of the
+// returned cubes. Comment lines (starting with "c") and empty lines are
+// ignored.
+//
+// Lines are parsed as clause lines: a 0 that is not the last token of a line
+// is an error wrapping ErrZeroLiteral. Parsing errors are reported as
+// *ParseError.
+func ReadCubes(r io.Reader) ([][]int, error) {
+	rd := Reader{}
+	rd.Reset(r)
+	cubes := [][]int{}
+	buf := make([]int, 0, 32)
+
+	for n := 1; ; n++ {
+		line, err := rd.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n == 1 {
+			line = bytes.TrimPrefix(line, []byte(utf8BOM))
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == 'c' {
+			continue
+		}
+
+		lits := line
+		if lits[0] == 'a' {
+			lits = lits[1:]
+		}
+		buf, _, err = parseClause(lits, buf[:0])
+		if err != nil {
+			return nil, newParseError(n, string(line), err)
+		}
+		cubes = append(cubes, append(make([]int, 0, len(buf)), buf...))
+	}
+	return cubes, nil
+}
@@ -0,0 +1,85 @@
+package dimacs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadCubes(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		input    string
+		want     [][]int
+		wantLine int
+		wantErr  bool
+	}{
+		{
+			desc:  "empty input",
+			input: "",
+			want:  [][]int{},
+		},
+		{
+			desc:  "cubes",
+			input: "1 -2 0\n3 0\n-1 2 -3 0\n",
+			want:  [][]int{{1, -2}, {3}, {-1, 2, -3}},
+		},
+		{
+			desc:  "comments and empty lines",
+			input: "c cubes\n\n1 -2 0\n   \nc next\n3 0",
+			want:  [][]int{{1, -2}, {3}},
+		},
+		{
+			desc:  "assumption prefix",
+			input: "a 1 -2 0\na -3 0\n",
+			want:  [][]int{{1, -2}, {-3}},
+		},
+		{
+			desc:  "empty cube",
+			input: "0\n",
+			want:  [][]int{{}},
+		},
+		{
+			desc:     "zero in the middle",
+			input:    "1 0\n1 0 -2 0\n",
+			wantLine: 2,
+			wantErr:  true,
+		},
+		{
+			desc:     "invalid literal",
+			input:    "1 -2 0\n\n1 x 0\n",
+			wantLine: 3,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotErr := ReadCubes(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Fatalf("ReadCubes(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Fatalf("ReadCubes(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ReadCubes(): cubes mismatch (-want +got):\n%s", diff)
+			}
+			var pe *ParseError
+			if tc.wantErr && (!errors.As(gotErr, &pe) || pe.Line != tc.wantLine) {
+				t.Errorf("ReadCubes(): want error on line %d, got %v", tc.wantLine, gotErr)
+			}
+		})
+	}
+}
+
+func TestReadCubes_zeroLiteral(t *testing.T) {
+	_, err := ReadCubes(strings.NewReader("1 0 2 0\n"))
+
+	if !errors.Is(err, ErrZeroLiteral) {
+		t.Errorf("ReadCubes(): want error %q, got %v", ErrZeroLiteral, err)
+	}
+}
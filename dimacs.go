@@ -1,9 +1,13 @@
 // Package dimacs provides utilities to read and parse CNF (Conjunctive Normal
-// Form) DIMACS files.
+// Form) DIMACS files, as well as closely related formats such as DRAT proofs,
+// DIMACS graphs, or OPB pseudo-Boolean instances.
 package dimacs
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -21,51 +25,360 @@ type CNFFormula struct {
 	Clauses [][]int
 }
 
-// ReadCNF parses and returns a DIMACS CNF formula from the given reader.
+// Clone returns a deep copy of the formula. The clauses of the copy do not
+// share memory with the clauses of f and can thus be modified independently.
+func (f CNFFormula) Clone() CNFFormula {
+	if f.Clauses == nil {
+		return CNFFormula{NumVars: f.NumVars}
+	}
+	clauses := make([][]int, len(f.Clauses))
+	for i, c := range f.Clauses {
+		clauses[i] = append([]int{}, c...)
+	}
+	return CNFFormula{NumVars: f.NumVars, Clauses: clauses}
+}
+
+// Equal returns true if f and other have the same number of variables and the
+// same clauses. If ignoreOrder is false, the clauses and their literals must
+// appear in the same order. Otherwise, each clause is considered as a set of
+// literals (i.e. order and repetitions of literals are ignored) and the formula
+// as a multiset of clauses (i.e. order of clauses is ignored but not their
+// number of occurrences).
+func (f CNFFormula) Equal(other CNFFormula, ignoreOrder bool) bool {
+	if f.NumVars != other.NumVars || len(f.Clauses) != len(other.Clauses) {
+		return false
+	}
+	if !ignoreOrder {
+		for i, c := range f.Clauses {
+			if !equalClauses(c, other.Clauses[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var tmp, buf []int
+	setKey := func(c []int) string {
+		var key string
+		tmp = uniqueLiterals(append(tmp[:0], c...))
+		key, buf = clauseKey(tmp, buf)
+		return key
+	}
+	counts := make(map[string]int, len(f.Clauses))
+	for _, c := range f.Clauses {
+		counts[setKey(c)]++
+	}
+	for _, c := range other.Clauses {
+		key := setKey(c)
+		if counts[key] == 0 {
+			return false
+		}
+		counts[key]--
+	}
+	return true
+}
+
+func equalClauses(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadCNF parses and returns a DIMACS CNF formula from the given reader. The
+// input must contain exactly one problem line, before any clause, and exactly
+// as many clauses as declared in it. See ReadCNFLenient and ReadCNFInferred to
+// relax these checks.
+//
+// A clause line that only contains the terminating 0 is the empty clause. It
+// is returned as a zero-length clause, which makes the formula trivially
+// unsatisfiable (see CNFFormula.IsTriviallyUnsat).
 func ReadCNF(r io.Reader) (CNFFormula, error) {
-	builder := cnfBuilder{}
-	if err := ReadBuilder(r, &builder); err != nil {
+	return ReadCNFContext(context.Background(), r)
+}
+
+// ReadCNFContext is like ReadCNF but stops parsing and returns the context's
+// error if ctx is done before the whole formula is read.
+func ReadCNFContext(ctx context.Context, r io.Reader) (CNFFormula, error) {
+	return readCNF(ctx, r, ReadCNFOptions{})
+}
+
+// ReadCNFOptions configures how ReadCNFWithOptions validates the formula. The
+// zero value corresponds to the behavior of ReadCNF.
+type ReadCNFOptions struct {
+	// IgnoreClauseCount disables the validation of the number of clauses
+	// declared in the problem line. The declared count is only used as a
+	// capacity hint and all the clauses in the input are returned, whether
+	// there are fewer or more of them than declared. The returned Clauses
+	// slice is trimmed to its length so that an over-declared count does not
+	// keep unused capacity alive.
+	IgnoreClauseCount bool
+
+	// InferCounts ignores the counts declared in the problem line, which
+	// becomes optional and only needs to declare a "cnf" problem (e.g. "p cnf"
+	// or "p cnf ? ?"). NumVars is then the largest variable appearing in the
+	// clauses and all the clauses in the input are returned (as with
+	// IgnoreClauseCount). The returned Clauses slice is trimmed to its length.
+	InferCounts bool
+
+	// CheckVarRange rejects the clauses that contain a variable greater than
+	// the number of variables declared in the problem line with an error
+	// wrapping ErrVarOutOfRange. It can be combined with IgnoreClauseCount
+	// when the declared number of variables is reliable but the declared
+	// number of clauses is not. It has no effect with InferCounts.
+	CheckVarRange bool
+
+	// ExactNumVars checks that the number of variables declared in the
+	// problem line is the largest variable appearing in the clauses (see
+	// CNFFormula.MaxVar), which catches common header mistakes. A formula
+	// that uses more variables than declared is rejected with an error
+	// wrapping ErrVarOutOfRange, and one whose last declared variables are
+	// unused with an error wrapping ErrInvalidProblemLine. It has no effect
+	// with InferCounts.
+	ExactNumVars bool
+
+	// RejectDuplicateLiterals rejects the clauses in which a literal appears
+	// more than once (e.g. "1 1 -2 0") with an error wrapping
+	// ErrDuplicateLiteral.
+	RejectDuplicateLiterals bool
+
+	// RejectTautologies rejects the clauses that contain both a literal and
+	// its negation (e.g. "1 -1 0") with an error wrapping ErrTautology.
+	RejectTautologies bool
+
+	// ContinueOnError skips the invalid lines rather than failing on the first
+	// of them (see ReadOptions.ContinueOnError). The formula made of the valid
+	// clauses is then returned, along with an ErrorList holding all the errors
+	// (including missing clauses) if any. The formula is empty if there is no
+	// valid problem line.
+	ContinueOnError bool
+
+	// CaseInsensitive accepts uppercase problem and comment lines (see
+	// ReadOptions.CaseInsensitive).
+	CaseInsensitive bool
+
+	// MultiLineClauses makes clauses span lines until their terminating 0
+	// (see ReadOptions.MultiLineClauses).
+	MultiLineClauses bool
+
+	// Arena stores the literals of all the clauses in a single backing array,
+	// each clause being a sub-slice of it. This saves one allocation per
+	// clause but the backing array is retained as long as any clause of the
+	// formula is, and the formula should thus be discarded as a whole. Clauses
+	// have no spare capacity so that appending to one of them never overwrites
+	// the next clause.
+	Arena bool
+}
+
+// readOptions returns the options of the underlying reader.
+func (o ReadCNFOptions) readOptions() ReadOptions {
+	return ReadOptions{
+		ContinueOnError:  o.ContinueOnError,
+		CaseInsensitive:  o.CaseInsensitive,
+		MultiLineClauses: o.MultiLineClauses,
+		ignoreCounts:     o.InferCounts,
+	}
+}
+
+// ReadCNFWithOptions is like ReadCNF but validates the formula according to
+// the given options.
+func ReadCNFWithOptions(r io.Reader, opts ReadCNFOptions) (CNFFormula, error) {
+	return readCNF(context.Background(), r, opts)
+}
+
+// ReadCNFArena is like ReadCNF but stores the clauses in a single backing array
+// (see ReadCNFOptions.Arena).
+func ReadCNFArena(r io.Reader) (CNFFormula, error) {
+	return readCNF(context.Background(), r, ReadCNFOptions{Arena: true})
+}
+
+// ReadCNFLenient is like ReadCNF but accepts any number of clauses, whether
+// fewer or more than declared in the problem line. NumVars is the number of
+// variables declared in the problem line and Clauses holds the clauses that
+// are actually present. The problem line is still required.
+func ReadCNFLenient(r io.Reader) (CNFFormula, error) {
+	return readCNF(context.Background(), r, ReadCNFOptions{IgnoreClauseCount: true})
+}
+
+// ReadCNFInferred is like ReadCNF but ignores the counts declared in the
+// problem line and tolerates its absence. NumVars is inferred from the largest
+// variable appearing in the clauses and the number of clauses is the number of
+// clauses actually present. Malformed clause lines are still rejected.
+func ReadCNFInferred(r io.Reader) (CNFFormula, error) {
+	return readCNF(context.Background(), r, ReadCNFOptions{InferCounts: true})
+}
+
+// ReadCNFWithLines is like ReadCNF but also returns the 1-based number of the
+// line where each clause starts (e.g. lines[i] is the line of the i-th
+// clause), which is useful to report problems found in the formula later on.
+func ReadCNFWithLines(r io.Reader) (f CNFFormula, lines []int, err error) {
+	b := lineCNFBuilder{}
+	nLines, err := readBuilder(context.Background(), r, &b, ReadOptions{})
+	if err != nil {
+		return CNFFormula{}, nil, err
+	}
+	f, err = b.formula()
+	if err != nil {
+		return CNFFormula{}, nil, newParseError(nLines, "", err)
+	}
+	return f, b.lines, nil
+}
+
+// lineCNFBuilder is a cnfBuilder that records the line of each clause.
+type lineCNFBuilder struct {
+	cnfBuilder
+	lines []int
+}
+
+func (b *lineCNFBuilder) ClauseAtLine(line int, tmp []int) error {
+	if err := b.cnfBuilder.Clause(tmp); err != nil {
+		return err
+	}
+	b.lines = append(b.lines, line)
+	return nil
+}
+
+func readCNF(ctx context.Context, r io.Reader, opts ReadCNFOptions) (CNFFormula, error) {
+	rd := Reader{}
+	rd.Reset(r)
+	return rd.readCNF(ctx, opts)
+}
+
+// readCNF reads a formula from the Reader's input, validating it according to
+// opts.
+func (rd *Reader) readCNF(ctx context.Context, opts ReadCNFOptions) (CNFFormula, error) {
+	builder := cnfBuilder{opts: opts}
+	if opts.ContinueOnError {
+		return rd.readCNFBestEffort(ctx, &builder)
+	}
+	lines, err := rd.read(ctx, &builder, opts.readOptions())
+	if err != nil {
 		return CNFFormula{}, err
 	}
-	if builder.cnf == nil {
-		return CNFFormula{}, fmt.Errorf("missing problem line found")
+	f, err := builder.formula()
+	if err != nil {
+		return CNFFormula{}, newParseError(lines, "", err)
+	}
+	return f, nil
+}
+
+// readCNFBestEffort implements ReadCNFOptions.ContinueOnError.
+func (rd *Reader) readCNFBestEffort(ctx context.Context, builder *cnfBuilder) (CNFFormula, error) {
+	lines, err := rd.read(ctx, builder, builder.opts.readOptions())
+	errs, ok := err.(ErrorList)
+	if err != nil && !ok {
+		return CNFFormula{}, err
 	}
-	if got, want := len(builder.cnf.Clauses), cap(builder.cnf.Clauses); got < want {
-		return CNFFormula{}, fmt.Errorf("missing clauses: expected %d, got %d", want, got)
+	f, err := builder.formula()
+	if err != nil {
+		if builder.cnf != nil {
+			f = *builder.cnf // missing clauses
+		}
+		if !errors.Is(err, ErrNoProblemLine) || !stoppedBeforeProblem(errs) {
+			errs = append(errs, newParseError(lines, "", err))
+		}
 	}
-	return *builder.cnf, nil
+	if len(errs) > 0 {
+		return f, errs
+	}
+	return f, nil
+}
+
+// stoppedBeforeProblem returns true if the last error of errs is one that
+// stops the reading before a valid problem line is found, in which case the
+// missing problem line is not worth reporting.
+func stoppedBeforeProblem(errs ErrorList) bool {
+	if len(errs) == 0 {
+		return false
+	}
+	switch errs[len(errs)-1].Kind {
+	case KindBadProblem, KindInvalidProblemType, KindClauseBeforeProblem:
+		return true
+	}
+	return false
 }
 
 type cnfBuilder struct {
-	cnf *CNFFormula
+	opts     ReadCNFOptions
+	cnf      *CNFFormula
+	nClauses int // declared number of clauses
+
+	// Literals of the clauses and end of each clause in lits if opts.Arena is
+	// set. The clauses are only sliced from lits once all of them are read as
+	// lits can be reallocated when growing.
+	lits []int
+	ends []int
 }
 
 func (b *cnfBuilder) Problem(p string, v int, c int) error {
 	if b.cnf != nil {
-		return fmt.Errorf("duplicate problem line")
+		return ErrDuplicateProblem
 	}
 	if p != "cnf" {
-		return fmt.Errorf("expected \"cnf\" problem, got %q", p)
+		return fmt.Errorf("%w: expected \"cnf\" problem, got %q", ErrInvalidProblemType, p)
+	}
+	if b.opts.InferCounts {
+		b.cnf = &CNFFormula{Clauses: [][]int{}}
+		return nil
 	}
 	if v < 0 {
-		return fmt.Errorf("number of variables must be non-negative, got: %d", v)
+		return fmt.Errorf("%w: number of variables must be non-negative, got: %d", ErrInvalidProblemLine, v)
 	}
 	if c < 0 {
-		return fmt.Errorf("number of clauses must be non-negative, got: %d", c)
+		return fmt.Errorf("%w: number of clauses must be non-negative, got: %d", ErrInvalidProblemLine, c)
 	}
 	b.cnf = &CNFFormula{
 		NumVars: v,
 		Clauses: make([][]int, 0, c),
 	}
+	b.nClauses = c
+	if b.opts.Arena {
+		b.ends = make([]int, 0, c)
+	}
 	return nil
 }
 
 func (b *cnfBuilder) Clause(tmp []int) error {
 	if b.cnf == nil {
-		return fmt.Errorf("clause found before problem line")
+		if !b.opts.InferCounts {
+			return ErrClauseBeforeProblem
+		}
+		b.cnf = &CNFFormula{Clauses: [][]int{}}
+	}
+	if b.opts.InferCounts {
+		for _, l := range tmp {
+			if v := abs(l); v > b.cnf.NumVars {
+				b.cnf.NumVars = v
+			}
+		}
+	} else if s := len(b.cnf.Clauses); s == b.nClauses && !b.opts.IgnoreClauseCount {
+		return &ClauseCountError{Declared: s, Actual: s + 1, TooMany: true}
+	}
+	if b.opts.CheckVarRange && !b.opts.InferCounts {
+		for _, l := range tmp {
+			if abs(l) > b.cnf.NumVars {
+				return fmt.Errorf("%w: literal %d, expected variables in 1..%d", ErrVarOutOfRange, l, b.cnf.NumVars)
+			}
+		}
+	}
+	if b.opts.RejectTautologies && IsTautology(tmp) {
+		return fmt.Errorf("%w: %v", ErrTautology, tmp)
+	}
+	if b.opts.RejectDuplicateLiterals {
+		if l, ok := duplicateLiteral(tmp); ok {
+			return fmt.Errorf("%w: literal %d appears more than once", ErrDuplicateLiteral, l)
+		}
 	}
-	if s := len(b.cnf.Clauses); s == cap(b.cnf.Clauses) {
-		return fmt.Errorf("too many clauses: expected %d", s)
+	if b.opts.Arena {
+		b.lits = append(b.lits, tmp...)
+		b.ends = append(b.ends, len(b.lits))
+		b.cnf.Clauses = append(b.cnf.Clauses, nil) // sliced in formula
+		return nil
 	}
 	c := make([]int, len(tmp))
 	copy(c, tmp)
@@ -75,85 +388,623 @@ func (b *cnfBuilder) Clause(tmp []int) error {
 
 func (b *cnfBuilder) Comment(c string) error { return nil } // ignore comments
 
+// formula validates and returns the formula once all the lines have been read.
+func (b *cnfBuilder) formula() (CNFFormula, error) {
+	if b.opts.Arena && b.cnf != nil {
+		start := 0
+		for i, end := range b.ends {
+			if start == end {
+				b.cnf.Clauses[i] = []int{} // as if not in an arena (lits may be nil)
+			} else {
+				b.cnf.Clauses[i] = b.lits[start:end:end]
+			}
+			start = end
+		}
+	}
+	if (b.opts.IgnoreClauseCount || b.opts.InferCounts) && b.cnf != nil {
+		b.cnf.Clauses = trimClauses(b.cnf.Clauses)
+	}
+	if b.opts.InferCounts {
+		if b.cnf == nil {
+			return CNFFormula{Clauses: [][]int{}}, nil
+		}
+		return *b.cnf, nil
+	}
+	if b.cnf == nil {
+		return CNFFormula{}, ErrNoProblemLine
+	}
+	if got, want := len(b.cnf.Clauses), b.nClauses; got < want && !b.opts.IgnoreClauseCount {
+		return CNFFormula{}, &ClauseCountError{Declared: want, Actual: got}
+	}
+	if b.opts.ExactNumVars {
+		switch m := b.cnf.MaxVar(); {
+		case m > b.cnf.NumVars:
+			return CNFFormula{}, fmt.Errorf("%w: variable %d used but only %d declared", ErrVarOutOfRange, m, b.cnf.NumVars)
+		case m < b.cnf.NumVars:
+			return CNFFormula{}, fmt.Errorf("%w: %d variables declared but the largest used is %d", ErrInvalidProblemLine, b.cnf.NumVars, m)
+		}
+	}
+	return *b.cnf, nil
+}
+
+// trimClauses returns clauses without spare capacity, copying them to a new
+// slice if needed so that the surplus capacity can be garbage collected.
+func trimClauses(clauses [][]int) [][]int {
+	if cap(clauses) == len(clauses) {
+		return clauses
+	}
+	trimmed := make([][]int, len(clauses))
+	copy(trimmed, clauses)
+	return trimmed
+}
+
 // Builder defines methods to construct a CNF formula from a DIMACS file.
 type Builder interface {
-	// Problem processes the problem line.
+	// Problem processes the problem line. The problem line of incremental CNF
+	// files ("p inccnf") does not declare any count, in which case nVars and
+	// nClauses are both -1.
 	Problem(problem string, nVars int, nClauses int) error
 
 	// Clause processes the clause from clause line. Implementations of this
 	// method should consider tmpClause as a shared buffer and only read from it
-	// without retaining it.
+	// without retaining it: its content is overwritten by the next clause line.
+	// Builders that process clauses on the fly (e.g. to compute statistics) can
+	// thus read tmpClause without any allocation, while builders that store
+	// clauses must copy them (as CollectBuilder does).
 	Clause(tmpClause []int) error
 
 	// Comment processes a comment line. Lines passed to this function always
-	// start with the comment prefix "c". This is useful to process additional
-	// information stored in the comments (e.g. problem information, solver
-	// configuration, etc.).
+	// start with the comment prefix ("c" unless configured otherwise with
+	// ReadOptions). This is useful to process additional information stored
+	// in the comments (e.g. problem information, solver configuration, etc.).
 	Comment(line string) error
 }
 
+// IndexedBuilder is a Builder that also receives the position of each clause.
+// If the builder passed to ReadBuilder implements IndexedBuilder, ClauseAt is
+// called instead of Clause for each clause.
+type IndexedBuilder interface {
+	Builder
+
+	// ClauseAt is like Clause but also receives the 0-based index of the
+	// clause among the clauses of the file.
+	ClauseAt(index int, tmpClause []int) error
+}
+
+// LineBuilder is a Builder that also receives the line of each clause. If the
+// builder passed to ReadBuilder implements LineBuilder, ClauseAtLine is called
+// instead of Clause (and of ClauseAt) for each clause.
+type LineBuilder interface {
+	Builder
+
+	// ClauseAtLine is like Clause but also receives the 1-based number of the
+	// line where the clause starts.
+	ClauseAtLine(line int, tmpClause []int) error
+}
+
 // ReadBuilder reads a DIMACS file from the given reader and populates
 // the given builder. Builder methods are called in the same order as the
 // corresponding lines (i.e. comment, problem, clause) in the DIMACS file.
+//
+// A comment can follow the terminating 0 of a clause on the same line (e.g.
+// "1 -2 0 c note"), in which case it is passed to the builder after the clause.
+// Tokens that follow the counts of the problem line (e.g. the trailing 0 of
+// "p cnf 3 4 0") are ignored.
+//
+// A line starting with "%" (typically a single "%", as found in the SATLIB
+// benchmarks) marks the end of the clauses, unless it is a comment line (i.e.
+// ReadOptions.CommentPrefix starts with "%"). Comment lines that follow it are
+// still passed to the builder while any other line (e.g. a trailing "0" or an
+// invalid clause) is silently ignored.
+//
+// Parsing errors, including the errors returned by the builder, are reported
+// as *ParseError. Errors returned by the reader are returned as is.
 func ReadBuilder(r io.Reader, b Builder) error {
-	scanner := bufio.NewScanner(r)
-	clauseBuf := make([]int, 32)
+	return ReadBuilderContext(context.Background(), r, b)
+}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
+// ReadOptions configures how ReadBuilderWithOptions reads DIMACS files. The
+// zero value corresponds to the behavior of ReadBuilder.
+type ReadOptions struct {
+	// CommentPrefix is the prefix that identifies comment lines (e.g. "*" or
+	// "#" for some DIMACS-derived formats). It defaults to "c" if empty.
+	CommentPrefix string
+
+	// ClauseWidthHint is the expected maximum number of literals per clause.
+	// It is used to size the clause buffer upfront, which avoids growing it
+	// while reading very wide clauses. By default, the buffer is sized from
+	// the number of variables declared in the problem line (up to a few
+	// thousands of literals).
+	ClauseWidthHint int
+
+	// RequireTerminator makes clause lines that do not end with a 0 (e.g.
+	// "1 -2") an error wrapping ErrUnterminatedClause, which helps detecting
+	// truncated files. By default, such lines are accepted as clauses.
+	RequireTerminator bool
+
+	// ContinueOnError makes the reader skip the comment, clause and assumption
+	// lines that cannot be parsed or that are rejected by the builder, and
+	// keep reading. Duplicate problem lines are skipped as well. The errors
+	// are then returned together as an ErrorList once the whole input is read,
+	// the builder having received all the valid lines. Other errors on the
+	// problem line and clauses found before it still stop the reading (their
+	// error being the last of the list), as do the errors of the underlying
+	// reader and of the context, which are returned as is.
+	ContinueOnError bool
+
+	// CaseInsensitive recognizes the problem and comment lines regardless of
+	// the case of their prefix and of the problem type (e.g. "P CNF 3 4" or
+	// "C note"), as written by some older tools. The problem type is passed
+	// to the builder in lowercase. By default, they must be lowercase.
+	CaseInsensitive bool
+
+	// MultiLineClauses makes clauses span lines until their terminating 0
+	// (e.g. "1 -2" followed by "3 0" is the single clause "1 -2 3 0"), as
+	// written by WriteCNFWrapped. Comment lines can appear between the lines
+	// of a clause and errors are reported on the line that ends the clause.
+	// A clause that is not terminated at the end of the input is an error
+	// wrapping ErrUnterminatedClause. By default, each line is a clause.
+	MultiLineClauses bool
+
+	// ignoreCounts skips the counts of the problem line, which are passed to
+	// the builder as -1 (see ReadCNFOptions.InferCounts).
+	ignoreCounts bool
+}
+
+func (o ReadOptions) commentPrefix() string {
+	if o.CommentPrefix == "" {
+		return "c"
+	}
+	return o.CommentPrefix
+}
+
+// ReadBuilderWithOptions is like ReadBuilder but reads the file according to
+// the given options.
+func ReadBuilderWithOptions(r io.Reader, b Builder, opts ReadOptions) error {
+	_, err := readBuilder(context.Background(), r, b, opts)
+	return err
+}
+
+// utf8BOM is the UTF-8 byte order mark that some tools write at the beginning
+// of text files.
+const utf8BOM = "\ufeff"
+
+// ctxCheckInterval is the number of lines read between two checks of the
+// context in ReadBuilderContext.
+const ctxCheckInterval = 4096
+
+// ReadBuilderContext is like ReadBuilder but stops parsing and returns the
+// context's error if ctx is done before the whole file is read. The context
+// is checked every few thousand lines to keep its overhead negligible.
+func ReadBuilderContext(ctx context.Context, r io.Reader, b Builder) error {
+	_, err := readBuilder(ctx, r, b, ReadOptions{})
+	return err
+}
+
+// readBuilder implements ReadBuilderContext and ReadBuilderWithOptions and
+// also returns the number of lines that were read.
+func readBuilder(ctx context.Context, r io.Reader, b Builder, opts ReadOptions) (int, error) {
+	rd := Reader{}
+	rd.Reset(r)
+	return rd.read(ctx, b, opts)
+}
+
+// read reads the DIMACS file from the underlying reader, populates b, and
+// returns the number of lines that were read.
+func (rd *Reader) read(ctx context.Context, b Builder, opts ReadOptions) (int, error) {
+	if rd.clauseBuf == nil {
+		rd.clauseBuf = make([]int, 32)
+	}
+	if cap(rd.clauseBuf) < opts.ClauseWidthHint {
+		rd.clauseBuf = make([]int, 0, opts.ClauseWidthHint)
+	}
+	clauseBuf := rd.clauseBuf
+	defer func() { rd.clauseBuf = clauseBuf[:0] }() // keep the grown buffer
+
+	ib, _ := b.(ICNFBuilder)
+	xb, _ := b.(XorBuilder)
+	idb, _ := b.(IndexedBuilder)
+	lb, _ := b.(LineBuilder)
+	nClauses := 0
+	pending := 0 // number of literals of a clause continued on the next line
+	clauseLine := 0
+	commentPrefix := []byte(opts.commentPrefix())
+	fold := opts.CaseInsensitive
+
+	// With opts.ContinueOnError, the errors of the comment, clause and
+	// assumption lines are recorded in errs and the offending lines skipped.
+	var errs ErrorList
+	skip := func(err *ParseError) bool {
+		if !opts.ContinueOnError || errors.Is(err, ErrClauseBeforeProblem) {
+			return false
 		}
-		if line == "%" { // end of file marker
+		errs = append(errs, err)
+		return true
+	}
+	fail := func(err *ParseError) error {
+		if opts.ContinueOnError {
+			return append(errs, err)
+		}
+		return err
+	}
+
+	n := 0
+	afterEnd := false
+	for ; ; n++ {
+		if n%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return n, err
+			}
+		}
+		line, err := rd.readLine()
+		if err == io.EOF {
 			break
 		}
+		if err != nil {
+			return n, err
+		}
+		if n == 0 {
+			line = bytes.TrimPrefix(line, []byte(utf8BOM))
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		isComment := hasPrefix(line, commentPrefix, fold)
+		if line[0] == '%' && !isComment { // end of clauses marker
+			afterEnd = true
+			continue
+		}
+		if afterEnd && !isComment {
+			continue // only comments are processed after the end marker
+		}
 
-		switch line[0] {
-		case 'c': // comment
-			if err := b.Comment(line); err != nil {
-				return err
+		switch {
+		case isComment:
+			if err := b.Comment(string(line)); err != nil {
+				if pe := newParseError(n+1, string(line), err); !skip(pe) {
+					return n + 1, fail(pe)
+				}
+				continue
+			}
+		case line[0] == 'p' || fold && line[0] == 'P': // problem
+			nVars, err := parseProblem(string(line), b, opts)
+			if err != nil {
+				if pe := err.withLine(n + 1); !errors.Is(pe, ErrDuplicateProblem) || !skip(pe) {
+					return n + 1, fail(pe)
+				}
+				continue
 			}
-		case 'p': // problem
-			parts := strings.Fields(line)
-			if len(parts) != 4 {
-				return fmt.Errorf("problem line should have 4 parts, got %d: %s", len(parts), line)
+			if w := seedClauseWidth(nVars); cap(clauseBuf) < w {
+				clauseBuf = make([]int, 0, w)
 			}
-			nVars, err := strconv.Atoi(parts[2])
+		case line[0] == 'a' && ib != nil: // assumptions
+			var err error
+			clauseBuf, _, err = parseClause(line[1:], clauseBuf[:0])
 			if err != nil {
-				return fmt.Errorf("invalid number of variables: %w", err)
+				if pe := newParseError(n+1, string(line), err); !skip(pe) {
+					return n + 1, fail(pe)
+				}
+				continue
+			}
+			if err := ib.Assume(clauseBuf); err != nil {
+				if pe := newParseError(n+1, string(line), err); !skip(pe) {
+					return n + 1, fail(pe)
+				}
+				continue
 			}
-			nClauses, err := strconv.Atoi(parts[3])
+		case line[0] == 'x' && xb != nil: // xor clause
+			var err error
+			clauseBuf, _, err = parseClause(line[1:], clauseBuf[:0])
 			if err != nil {
-				return fmt.Errorf("invalid number of clauses: %w", err)
+				if pe := newParseError(n+1, string(line), err); !skip(pe) {
+					return n + 1, fail(pe)
+				}
+				continue
 			}
-			if err := b.Problem(parts[1], nVars, nClauses); err != nil {
-				return err
+			if err := xb.XorClause(clauseBuf); err != nil {
+				if pe := newParseError(n+1, string(line), err); !skip(pe) {
+					return n + 1, fail(pe)
+				}
+				continue
 			}
 		default: // clause
-			clauseBuf = clauseBuf[:0]
-			parts := strings.Fields(line)
-			for i, p := range parts {
-				l, err := strconv.Atoi(p)
-				if err != nil {
-					return fmt.Errorf("invalid literal in clause %q: %w", line, err)
+			var comment []byte
+			var terminated bool
+			var err error
+			if pending == 0 {
+				clauseLine = n + 1
+			}
+			clauseBuf, terminated, err = parseClause(line, clauseBuf[:pending])
+			if errors.Is(err, ErrZeroLiteral) {
+				// The 0 might be followed by a comment (e.g. "1 -2 0 c note").
+				if i := trailingComment(line, commentPrefix, fold); i >= 0 {
+					comment = line[i:]
+					clauseBuf, terminated, err = parseClause(line[:i], clauseBuf[:pending])
+				}
+			}
+			if err == nil && !terminated && opts.MultiLineClauses {
+				pending = len(clauseBuf)
+				continue
+			}
+			pending = 0
+			if err == nil && !terminated && opts.RequireTerminator {
+				err = ErrUnterminatedClause
+			}
+			if err != nil {
+				if pe := newParseError(n+1, string(line), err); !skip(pe) {
+					return n + 1, fail(pe)
 				}
-				if l == 0 {
-					if i != len(parts)-1 {
-						return fmt.Errorf("zero found before end of clause line: %q", line)
+				continue
+			}
+			switch {
+			case lb != nil:
+				err = lb.ClauseAtLine(clauseLine, clauseBuf)
+			case idb != nil:
+				err = idb.ClauseAt(nClauses, clauseBuf)
+			default:
+				err = b.Clause(clauseBuf)
+			}
+			if err != nil {
+				if pe := newParseError(n+1, string(line), err); !skip(pe) {
+					return n + 1, fail(pe)
+				}
+				continue
+			}
+			nClauses++
+			if comment != nil {
+				if err := b.Comment(string(comment)); err != nil {
+					if pe := newParseError(n+1, string(line), err); !skip(pe) {
+						return n + 1, fail(pe)
 					}
-					break
+					continue
 				}
-				clauseBuf = append(clauseBuf, l)
 			}
-			if err := b.Clause(clauseBuf); err != nil {
-				return err
+		}
+	}
+
+	if pending > 0 {
+		if pe := newParseError(n, "", ErrUnterminatedClause); !skip(pe) {
+			return n, fail(pe)
+		}
+	}
+	if len(errs) > 0 {
+		return n, errs
+	}
+	return n, nil
+}
+
+// readLine returns the next line of the underlying reader, including its
+// trailing newline if any, or io.EOF if there are no more lines. The returned
+// slice is only valid until the next call to readLine.
+func (rd *Reader) readLine() ([]byte, error) {
+	if rd.fromBytes {
+		if len(rd.data) == 0 {
+			return nil, io.EOF
+		}
+		line := rd.data
+		if i := bytes.IndexByte(line, '\n'); i >= 0 {
+			line = line[:i+1]
+		}
+		rd.data = rd.data[len(line):]
+		return line, nil
+	}
+	line, err := rd.br.ReadSlice('\n')
+	if err == bufio.ErrBufferFull { // the line is longer than the buffer
+		rd.lineBuf = append(rd.lineBuf[:0], line...)
+		for err == bufio.ErrBufferFull {
+			line, err = rd.br.ReadSlice('\n')
+			rd.lineBuf = append(rd.lineBuf, line...)
+		}
+		line = rd.lineBuf
+	}
+	switch {
+	case err == io.EOF && len(line) > 0:
+		return line, nil // last line without a trailing newline
+	case err != nil:
+		return nil, err
+	default:
+		return line, nil
+	}
+}
+
+// parseProblem parses the problem line, passes it to b, and returns the
+// declared number of variables (-1 if none). Tokens after the counts are
+// ignored, as are the counts themselves if opts.ignoreCounts is set. The
+// problem type is lowercased if opts.CaseInsensitive is true.
+func parseProblem(line string, b Builder, opts ReadOptions) (int, *ParseError) {
+	parts := strings.Fields(line)
+	if opts.CaseInsensitive && len(parts) > 1 {
+		parts[1] = strings.ToLower(parts[1])
+	}
+	if opts.ignoreCounts && len(parts) >= 2 {
+		if err := b.Problem(parts[1], -1, -1); err != nil {
+			return 0, newParseError(0, line, err)
+		}
+		return -1, nil
+	}
+	if len(parts) == 2 && parts[1] == "inccnf" {
+		if err := b.Problem(parts[1], -1, -1); err != nil {
+			return 0, newParseError(0, line, err)
+		}
+		return -1, nil
+	}
+	if len(parts) < 4 {
+		err := fmt.Errorf("%w: should have at least 4 parts, got %d: %s", ErrInvalidProblemLine, len(parts), line)
+		return 0, newParseError(0, line, err)
+	}
+	nVars, err := strconv.Atoi(parts[2])
+	if err != nil {
+		err = &countSyntaxError{Count: "variable", Value: parts[2], Err: err}
+		return 0, newParseError(0, line, err)
+	}
+	nClauses, err := strconv.Atoi(parts[3])
+	if err != nil {
+		err = &countSyntaxError{Count: "clause", Value: parts[3], Err: err}
+		return 0, newParseError(0, line, err)
+	}
+	if err := b.Problem(parts[1], nVars, nClauses); err != nil {
+		return 0, newParseError(0, line, err)
+	}
+	return nVars, nil
+}
+
+// countSyntaxError is the error of a problem line count that is not an
+// integer. It wraps the error of strconv and matches ErrInvalidProblemLine.
+type countSyntaxError struct {
+	Count string // "variable" or "clause"
+	Value string
+	Err   error
+}
+
+func (e *countSyntaxError) Error() string {
+	return fmt.Sprintf("problem line has non-integer %s count %q", e.Count, e.Value)
+}
+
+func (e *countSyntaxError) Unwrap() error { return e.Err }
+
+func (e *countSyntaxError) Is(target error) bool { return target == ErrInvalidProblemLine }
+
+// maxSeedClauseWidth bounds the capacity of the clause buffer allocated from
+// the number of variables declared in the problem line. Wider clauses are
+// still supported but grow the buffer as they are read.
+const maxSeedClauseWidth = 1 << 12
+
+// seedClauseWidth returns the capacity of the clause buffer to allocate for a
+// formula with nVars variables, none of its clauses being wider than nVars
+// (except for repeated literals).
+func seedClauseWidth(nVars int) int {
+	if nVars > maxSeedClauseWidth {
+		return maxSeedClauseWidth
+	}
+	return nVars
+}
+
+// ScanClause parses a line made of integers terminated by a 0, as the clause
+// lines of DIMACS files (e.g. "1 -2 3 0"), and appends its integers (without
+// the terminating 0) to buf. It reports whether the line is terminated by a 0,
+// which is optional in CNF files but required by some DIMACS-like formats.
+// Tokens are separated by ASCII white space. An error is returned if a token
+// is not an integer or if a 0 is followed by other tokens, in which case the
+// error wraps ErrZeroLiteral.
+//
+// ScanClause is meant to implement readers for the formats that share this
+// line grammar. Prefixes that are not part of the integer list (e.g. the "d"
+// of DRAT deletion lines) must be removed from the line beforehand.
+func ScanClause(line string, buf []int) ([]int, bool, error) {
+	return parseClause(line, buf)
+}
+
+// parseClause parses the literals of a clause line, appends them (without the
+// terminating 0) to buf and returns the extended buffer. It also reports
+// whether the line is terminated by a 0. A 0 anywhere else in the line is an
+// error.
+//
+// Literals are parsed directly from the line without allocating, except for
+// unusual tokens (e.g. "+1" or huge numbers) that are delegated to strconv.
+func parseClause[T ~string | ~[]byte](line T, buf []int) ([]int, bool, error) {
+	i := 0
+	for {
+		for i < len(line) && isSpace(line[i]) {
+			i++
+		}
+		if i == len(line) {
+			return buf, false, nil
+		}
+		start := i
+		for i < len(line) && !isSpace(line[i]) {
+			i++
+		}
+		l, ok := parseLiteral(line[start:i])
+		if !ok {
+			var err error
+			if l, err = strconv.Atoi(string(line[start:i])); err != nil {
+				return buf, false, fmt.Errorf("invalid literal in clause %q: %w", line, err)
+			}
+		}
+		if l == 0 {
+			for i < len(line) && isSpace(line[i]) {
+				i++
+			}
+			if i != len(line) {
+				return buf, false, fmt.Errorf("%w: %q", ErrZeroLiteral, line)
 			}
+			return buf, true, nil
 		}
+		buf = append(buf, l)
 	}
+}
 
-	if err := scanner.Err(); err != nil {
-		return err
+// trailingComment returns the index of the comment that follows the
+// terminating 0 of a clause line (e.g. "c note" in "1 -2 0 c note"), or -1 if
+// the first 0 of the line is not followed by a comment.
+func trailingComment(line []byte, prefix []byte, fold bool) int {
+	i := 0
+	for i < len(line) {
+		for i < len(line) && isSpace(line[i]) {
+			i++
+		}
+		start := i
+		for i < len(line) && !isSpace(line[i]) {
+			i++
+		}
+		if l, ok := parseLiteral(line[start:i]); ok && l == 0 {
+			for i < len(line) && isSpace(line[i]) {
+				i++
+			}
+			if i == len(line) || !hasPrefix(line[i:], prefix, fold) {
+				return -1
+			}
+			return i
+		}
 	}
+	return -1
+}
 
-	return nil
+// hasPrefix returns true if s begins with prefix, ignoring case if fold is
+// true.
+func hasPrefix(s, prefix []byte, fold bool) bool {
+	if !fold {
+		return bytes.HasPrefix(s, prefix)
+	}
+	return len(s) >= len(prefix) && bytes.EqualFold(s[:len(prefix)], prefix)
+}
+
+// maxFastDigits is the maximum number of digits of the literals handled by
+// parseLiteral: 18 if int is 64 bits and 9 if it is 32 bits, so that any such
+// literal fits in an int without overflow. Longer literals are delegated to
+// strconv, which reports out of range values.
+const maxFastDigits = 9 * strconv.IntSize / 32
+
+// parseLiteral parses tok as an optionally negative decimal integer. It returns
+// false if tok is not of this form or has too many digits for a fast parse.
+func parseLiteral[T ~string | ~[]byte](tok T) (int, bool) {
+	neg := len(tok) > 0 && tok[0] == '-'
+	if neg {
+		tok = tok[1:]
+	}
+	if len(tok) == 0 || len(tok) > maxFastDigits {
+		return 0, false
+	}
+	v := 0
+	for i := 0; i < len(tok); i++ {
+		d := tok[i] - '0'
+		if d > 9 {
+			return 0, false
+		}
+		v = v*10 + int(d)
+	}
+	if neg {
+		v = -v
+	}
+	return v, true
+}
+
+// isSpace reports whether c is an ASCII white space character.
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
 }
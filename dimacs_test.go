@@ -2,7 +2,10 @@ package dimacs
 
 import (
 	"errors"
+	"fmt"
 	"io"
+	"math/rand"
+	"strconv"
 	"strings"
 	"testing"
 	"testing/iotest"
@@ -42,6 +45,23 @@ p cnf 3 4
 c comment 
 `
 
+const validCNF_crlf = "c comment 1\r\n" +
+	"p cnf 3 4\r\n" +
+	"1 2 3 0\r\n" +
+	"c comment 2\r\n" +
+	"1 -2 3 0\r\n" +
+	"\r\n" +
+	"1\r-3 0\r\n" +
+	"-2 -3 0\r\n" +
+	"%\r\n" +
+	"0\r\n"
+
+const validCNF_bom = "\ufeffp cnf 3 4\r\n" +
+	"1 2 3 0\r\n" +
+	"1 -2 3 0\r\n" +
+	"1 -3 0\r\n" +
+	"-2 -3 0\r\n"
+
 func TestRead(t *testing.T) {
 	testCases := []struct {
 		desc    string
@@ -181,6 +201,69 @@ func TestRead(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			desc:   "valid cnf (CRLF line endings)",
+			reader: strings.NewReader(validCNF_crlf),
+			wantCNF: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{
+					{1, 2, 3},
+					{1, -2, 3},
+					{1, -3},
+					{-2, -3},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			desc:   "valid cnf (byte order mark)",
+			reader: strings.NewReader(validCNF_bom),
+			wantCNF: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{
+					{1, 2, 3},
+					{1, -2, 3},
+					{1, -3},
+					{-2, -3},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			desc:   "valid cnf (byte order mark before comment)",
+			reader: strings.NewReader("\ufeffc comment\r\n" + validCNF_crlf),
+			wantCNF: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{
+					{1, 2, 3},
+					{1, -2, 3},
+					{1, -3},
+					{-2, -3},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			desc:   "empty clause",
+			reader: strings.NewReader("p cnf 2 2\n1 -2 0\n0\n"),
+			wantCNF: CNFFormula{
+				NumVars: 2,
+				Clauses: [][]int{{1, -2}, {}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "byte order mark after first line",
+			reader:  strings.NewReader("p cnf 3 1\n\ufeff1 2 3 0\n"),
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:    "invalid literal (CRLF line endings)",
+			reader:  strings.NewReader("p cnf 3 1\r\n1 a 3 0\r\n"),
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -200,6 +283,101 @@ func TestRead(t *testing.T) {
 	}
 }
 
+func TestClone(t *testing.T) {
+	f := CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {}, {3}}}
+
+	got := f.Clone()
+
+	if diff := cmp.Diff(f, got); diff != "" {
+		t.Fatalf("Clone(): CNF mismatch (-want +got):\n%s", diff)
+	}
+	got.Clauses[0][0] = 2
+	got.Clauses[2] = append(got.Clauses[2], 1)
+	got.Clauses = append(got.Clauses, []int{-1})
+	want := CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {}, {3}}}
+	if diff := cmp.Diff(want, f); diff != "" {
+		t.Errorf("Clone(): original modified (-want +got):\n%s", diff)
+	}
+}
+
+func TestClone_nilClauses(t *testing.T) {
+	f := CNFFormula{NumVars: 2}
+
+	got := f.Clone()
+
+	if diff := cmp.Diff(f, got); diff != "" {
+		t.Errorf("Clone(): CNF mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	f := CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {3}, {1, -2}}}
+
+	testCases := []struct {
+		desc            string
+		other           CNFFormula
+		wantOrdered     bool
+		wantIgnoreOrder bool
+	}{
+		{
+			desc:            "identical",
+			other:           CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {3}, {1, -2}}},
+			wantOrdered:     true,
+			wantIgnoreOrder: true,
+		},
+		{
+			desc:            "different number of variables",
+			other:           CNFFormula{NumVars: 4, Clauses: [][]int{{1, -2}, {3}, {1, -2}}},
+			wantOrdered:     false,
+			wantIgnoreOrder: false,
+		},
+		{
+			desc:            "reordered clauses and literals",
+			other:           CNFFormula{NumVars: 3, Clauses: [][]int{{-2, 1}, {1, -2}, {3}}},
+			wantOrdered:     false,
+			wantIgnoreOrder: true,
+		},
+		{
+			desc:            "repeated literals",
+			other:           CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2, 1}, {3, 3}, {-2, 1}}},
+			wantOrdered:     false,
+			wantIgnoreOrder: true,
+		},
+		{
+			desc:            "different clause multiplicity",
+			other:           CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {3}, {3}}},
+			wantOrdered:     false,
+			wantIgnoreOrder: false,
+		},
+		{
+			desc:            "different clause",
+			other:           CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {-3}, {1, -2}}},
+			wantOrdered:     false,
+			wantIgnoreOrder: false,
+		},
+		{
+			desc:            "missing clause",
+			other:           CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {3}}},
+			wantOrdered:     false,
+			wantIgnoreOrder: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := f.Equal(tc.other, false); got != tc.wantOrdered {
+				t.Errorf("Equal(ignoreOrder=false): want %t, got %t", tc.wantOrdered, got)
+			}
+			if got := f.Equal(tc.other, true); got != tc.wantIgnoreOrder {
+				t.Errorf("Equal(ignoreOrder=true): want %t, got %t", tc.wantIgnoreOrder, got)
+			}
+			if got := tc.other.Equal(f, true); got != tc.wantIgnoreOrder {
+				t.Errorf("Equal(ignoreOrder=true): not symmetric, want %t, got %t", tc.wantIgnoreOrder, got)
+			}
+		})
+	}
+}
+
 type testBuilder struct {
 	ProblemErr, ClauseErr, CommentErr error
 }
@@ -227,17 +405,17 @@ func TestReadBuilder(t *testing.T) {
 		{
 			desc:    "problem error",
 			builder: &testBuilder{ProblemErr: errors.New("problem error")},
-			wantErr: errors.New("problem error"),
+			wantErr: errors.New("line 4: problem error"),
 		},
 		{
 			desc:    "clause error",
 			builder: &testBuilder{ClauseErr: errors.New("clause error")},
-			wantErr: errors.New("clause error"),
+			wantErr: errors.New("line 6: clause error"),
 		},
 		{
 			desc:    "comment error",
 			builder: &testBuilder{CommentErr: errors.New("comment error")},
-			wantErr: errors.New("comment error"),
+			wantErr: errors.New("line 2: comment error"),
 		},
 		{
 			desc:    "no error",
@@ -258,3 +436,1394 @@ func TestReadBuilder(t *testing.T) {
 		})
 	}
 }
+
+type commentRecorder struct {
+	testBuilder
+	comments []string
+}
+
+func (cr *commentRecorder) Comment(line string) error {
+	cr.comments = append(cr.comments, line)
+	return nil
+}
+
+func TestReadBuilder_crlfComments(t *testing.T) {
+	cr := &commentRecorder{}
+
+	if err := ReadBuilder(strings.NewReader(validCNF_crlf), cr); err != nil {
+		t.Fatalf("ReadBuilder(): want no error, got %s", err)
+	}
+
+	want := []string{"c comment 1", "c comment 2"}
+	if diff := cmp.Diff(want, cr.comments); diff != "" {
+		t.Errorf("ReadBuilder(): comments mismatch (-want +got):\n%s", diff)
+	}
+}
+
+type indexRecorder struct {
+	testBuilder
+	indices []int
+	clauses [][]int
+}
+
+func (ir *indexRecorder) Clause(_ []int) error {
+	return errors.New("Clause called instead of ClauseAt")
+}
+
+func (ir *indexRecorder) ClauseAt(index int, tmpClause []int) error {
+	ir.indices = append(ir.indices, index)
+	ir.clauses = append(ir.clauses, append([]int{}, tmpClause...))
+	return nil
+}
+
+func TestReadBuilder_indexedBuilder(t *testing.T) {
+	ir := &indexRecorder{}
+
+	if err := ReadBuilder(strings.NewReader(validCNF_manyComments), ir); err != nil {
+		t.Fatalf("ReadBuilder(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff([]int{0, 1, 2, 3}, ir.indices); diff != "" {
+		t.Errorf("ReadBuilder(): indices mismatch (-want +got):\n%s", diff)
+	}
+	want := [][]int{{1, 2, 3}, {1, -2, 3}, {1, -3}, {-2, -3}}
+	if diff := cmp.Diff(want, ir.clauses); diff != "" {
+		t.Errorf("ReadBuilder(): clauses mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadCNFWithLines(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		input     string
+		want      CNFFormula
+		wantLines []int
+		wantErr   bool
+	}{
+		{
+			desc:      "one clause per line",
+			input:     "p cnf 2 2\n1 -2 0\n2 0\n",
+			want:      CNFFormula{NumVars: 2, Clauses: [][]int{{1, -2}, {2}}},
+			wantLines: []int{2, 3},
+		},
+		{
+			desc:      "comments and empty lines",
+			input:     "c header\np cnf 2 3\n\nc note\n1 -2 0 c trailing\n\n2 0\n0\n",
+			want:      CNFFormula{NumVars: 2, Clauses: [][]int{{1, -2}, {2}, {}}},
+			wantLines: []int{5, 7, 8},
+		},
+		{
+			desc:    "invalid clause",
+			input:   "p cnf 2 2\n1 -2 0\n2 x 0\n",
+			want:    CNFFormula{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotLines, gotErr := ReadCNFWithLines(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadCNFWithLines(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadCNFWithLines(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ReadCNFWithLines(): CNF mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantLines, gotLines); diff != "" {
+				t.Errorf("ReadCNFWithLines(): lines mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type lineRecorder struct {
+	testBuilder
+	lines []int
+}
+
+func (lr *lineRecorder) ClauseAtLine(line int, _ []int) error {
+	lr.lines = append(lr.lines, line)
+	return nil
+}
+
+func TestReadBuilder_lineBuilderMultiLine(t *testing.T) {
+	input := "p cnf 3 2\n1\nc note\n-2 3 0\n\n-1\n0\n"
+	lr := &lineRecorder{}
+
+	if err := ReadBuilderWithOptions(strings.NewReader(input), lr, ReadOptions{MultiLineClauses: true}); err != nil {
+		t.Fatalf("ReadBuilderWithOptions(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff([]int{2, 6}, lr.lines); diff != "" {
+		t.Errorf("ReadBuilderWithOptions(): lines mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadCNFWithOptions(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		opts    ReadCNFOptions
+		wantCNF CNFFormula
+		wantErr bool
+	}{
+		{
+			desc:    "strict (too many clauses)",
+			input:   "p cnf 3 1\n1 2 3 0\n-1 0\n",
+			opts:    ReadCNFOptions{},
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:    "strict (missing clauses)",
+			input:   "p cnf 3 3\n1 2 3 0\n-1 0\n",
+			opts:    ReadCNFOptions{},
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:  "ignore clause count (too many clauses)",
+			input: "p cnf 3 1\n1 2 3 0\n-1 0\n",
+			opts:  ReadCNFOptions{IgnoreClauseCount: true},
+			wantCNF: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{1, 2, 3}, {-1}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "ignore clause count (missing clauses)",
+			input: "p cnf 3 3\n1 2 3 0\n-1 0\n",
+			opts:  ReadCNFOptions{IgnoreClauseCount: true},
+			wantCNF: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{1, 2, 3}, {-1}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "ignore clause count (placeholder count)",
+			input: "p cnf 3 0\n1 2 3 0\n-1 0\n",
+			opts:  ReadCNFOptions{IgnoreClauseCount: true},
+			wantCNF: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{1, 2, 3}, {-1}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "duplicate literals allowed by default",
+			input: "p cnf 2 2\n1 1 -2 0\n1 -1 0\n",
+			opts:  ReadCNFOptions{},
+			wantCNF: CNFFormula{
+				NumVars: 2,
+				Clauses: [][]int{{1, 1, -2}, {1, -1}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "reject duplicate literals",
+			input:   "p cnf 2 2\n1 -2 0\n-2 1 -2 0\n",
+			opts:    ReadCNFOptions{RejectDuplicateLiterals: true},
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:  "reject duplicate literals (tautology)",
+			input: "p cnf 2 1\n1 -1 0\n",
+			opts:  ReadCNFOptions{RejectDuplicateLiterals: true},
+			wantCNF: CNFFormula{
+				NumVars: 2,
+				Clauses: [][]int{{1, -1}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "reject tautologies",
+			input:   "p cnf 2 2\n1 -2 0\n2 1 -2 0\n",
+			opts:    ReadCNFOptions{RejectTautologies: true},
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:  "variable out of range allowed by default",
+			input: "p cnf 2 1\n1 -3 0\n",
+			opts:  ReadCNFOptions{},
+			wantCNF: CNFFormula{
+				NumVars: 2,
+				Clauses: [][]int{{1, -3}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "check variable range",
+			input:   "p cnf 2 2\n1 -2 0\n1 -3 0\n",
+			opts:    ReadCNFOptions{CheckVarRange: true},
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:  "check variable range (ignore clause count)",
+			input: "p cnf 2 0\n1 -2 0\n2 0\n",
+			opts:  ReadCNFOptions{CheckVarRange: true, IgnoreClauseCount: true},
+			wantCNF: CNFFormula{
+				NumVars: 2,
+				Clauses: [][]int{{1, -2}, {2}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "check variable range (ignore clause count, out of range)",
+			input:   "p cnf 2 0\n1 -2 0\n3 0\n",
+			opts:    ReadCNFOptions{CheckVarRange: true, IgnoreClauseCount: true},
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:  "check variable range (infer counts)",
+			input: "p cnf 2 1\n1 -3 0\n",
+			opts:  ReadCNFOptions{CheckVarRange: true, InferCounts: true},
+			wantCNF: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{1, -3}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "exact number of variables",
+			input: "p cnf 3 2\n1 -2 0\n-3 0\n",
+			opts:  ReadCNFOptions{ExactNumVars: true},
+			wantCNF: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{1, -2}, {-3}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "exact number of variables (unused variables)",
+			input:   "p cnf 4 2\n1 -2 0\n-3 0\n",
+			opts:    ReadCNFOptions{ExactNumVars: true},
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:    "exact number of variables (under-declared)",
+			input:   "p cnf 2 2\n1 -2 0\n-3 0\n",
+			opts:    ReadCNFOptions{ExactNumVars: true},
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:  "tab-separated problem line",
+			input: "p\tcnf\t2 \t1\n1 -2 0\n",
+			opts:  ReadCNFOptions{},
+			wantCNF: CNFFormula{
+				NumVars: 2,
+				Clauses: [][]int{{1, -2}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "problem line with trailing tokens",
+			input: "p cnf 2 1 0\n1 -2 0\n",
+			opts:  ReadCNFOptions{},
+			wantCNF: CNFFormula{
+				NumVars: 2,
+				Clauses: [][]int{{1, -2}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "problem line with too few fields",
+			input:   "p cnf 2\n1 -2 0\n",
+			opts:    ReadCNFOptions{},
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:    "ignore clause count (no problem line)",
+			input:   "1 2 3 0\n",
+			opts:    ReadCNFOptions{IgnoreClauseCount: true},
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotCNF, gotErr := ReadCNFWithOptions(strings.NewReader(tc.input), tc.opts)
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadCNFWithOptions(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadCNFWithOptions(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantCNF, gotCNF); diff != "" {
+				t.Errorf("ReadCNFWithOptions(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadBuilder_afterEndMarker(t *testing.T) {
+	input := "p cnf 3 1\n1 2 3 0\n%\n0\nc after end 1\nnot a clause\np cnf 1 1\nc after end 2\n"
+	cr := &commentRecorder{}
+
+	if err := ReadBuilder(strings.NewReader(input), cr); err != nil {
+		t.Fatalf("ReadBuilder(): want no error, got %s", err)
+	}
+
+	want := []string{"c after end 1", "c after end 2"}
+	if diff := cmp.Diff(want, cr.comments); diff != "" {
+		t.Errorf("ReadBuilder(): comments mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadCNF_garbageAfterEndMarker(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		wantCNF CNFFormula
+		wantErr bool
+	}{
+		{
+			desc:    "extra clauses",
+			input:   "p cnf 3 1\n1 -2 3 0\n%\n0\n1 2 0\n-3 0\n",
+			wantCNF: CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2, 3}}},
+			wantErr: false,
+		},
+		{
+			desc:    "invalid lines",
+			input:   "p cnf 3 1\n1 -2 3 0\n%\n1 x 0\n0 0 0\np cnf 1 1\n%\nsome junk\n",
+			wantCNF: CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2, 3}}},
+			wantErr: false,
+		},
+		{
+			desc:    "marker with trailing text",
+			input:   "p cnf 3 1\n1 -2 3 0\n% end of clauses\n1 x 0\n",
+			wantCNF: CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2, 3}}},
+			wantErr: false,
+		},
+		{
+			desc:    "double marker",
+			input:   "p cnf 3 1\n1 -2 3 0\n%%\n-1 % 0\n",
+			wantCNF: CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2, 3}}},
+			wantErr: false,
+		},
+		{
+			desc:    "missing clauses before marker",
+			input:   "p cnf 3 2\n1 -2 3 0\n%\n-1 0\n",
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+	}
+
+	readers := []struct {
+		name string
+		read func(string) (CNFFormula, error)
+	}{
+		{"ReadCNF", func(s string) (CNFFormula, error) { return ReadCNF(strings.NewReader(s)) }},
+		{"ReadCNFBytes", func(s string) (CNFFormula, error) { return ReadCNFBytes([]byte(s)) }},
+		{"ReadCNFArena", func(s string) (CNFFormula, error) { return ReadCNFArena(strings.NewReader(s)) }},
+		{"ReadCNFParallel", func(s string) (CNFFormula, error) { return ReadCNFParallel(strings.NewReader(s), 2) }},
+	}
+
+	for _, tc := range testCases {
+		for _, rd := range readers {
+			t.Run(tc.desc+"/"+rd.name, func(t *testing.T) {
+				gotCNF, gotErr := rd.read(tc.input)
+
+				if tc.wantErr && gotErr == nil {
+					t.Errorf("%s(): want error, got nil", rd.name)
+				}
+				if !tc.wantErr && gotErr != nil {
+					t.Errorf("%s(): want no error, got %s", rd.name, gotErr)
+				}
+				if diff := cmp.Diff(tc.wantCNF, gotCNF); diff != "" {
+					t.Errorf("%s(): CNF mismatch (-want +got):\n%s", rd.name, diff)
+				}
+			})
+		}
+	}
+}
+
+func TestReadCNFLenient(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		wantCNF CNFFormula
+		wantErr bool
+	}{
+		{
+			desc:    "exact count",
+			input:   validCNF_manyComments,
+			wantCNF: testFormula,
+			wantErr: false,
+		},
+		{
+			desc:  "over-declared",
+			input: "p cnf 5 10\n1 -2 0\n-4 0\n",
+			wantCNF: CNFFormula{
+				NumVars: 5,
+				Clauses: [][]int{{1, -2}, {-4}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "under-declared",
+			input: "p cnf 5 1\n1 -2 0\n-4 0\n",
+			wantCNF: CNFFormula{
+				NumVars: 5,
+				Clauses: [][]int{{1, -2}, {-4}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "no problem line",
+			input:   "1 -2 0\n",
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotCNF, gotErr := ReadCNFLenient(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadCNFLenient(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadCNFLenient(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantCNF, gotCNF); diff != "" {
+				t.Errorf("ReadCNFLenient(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadCNFWithOptions_continueOnError(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		input     string
+		wantCNF   CNFFormula
+		wantLines []int
+		wantKinds []ErrorKind
+	}{
+		{
+			desc:    "valid formula",
+			input:   validCNF_manyComments,
+			wantCNF: testFormula,
+		},
+		{
+			desc:      "invalid clauses",
+			input:     "p cnf 3 3\n1 x 0\n1 -2 0\n1 0 2 0\n-3 0\n2 0\n",
+			wantCNF:   CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {-3}, {2}}},
+			wantLines: []int{2, 4},
+			wantKinds: []ErrorKind{KindBadLiteral, KindZeroMidClause},
+		},
+		{
+			desc:      "too many clauses",
+			input:     "p cnf 3 1\n1 -2 0\n3 0\n-1 0\n",
+			wantCNF:   CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}}},
+			wantLines: []int{3, 4},
+			wantKinds: []ErrorKind{KindTooManyClauses, KindTooManyClauses},
+		},
+		{
+			desc:      "missing clauses",
+			input:     "p cnf 3 3\n1 -2 0\n3 y 0\n",
+			wantCNF:   CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}}},
+			wantLines: []int{3, 3},
+			wantKinds: []ErrorKind{KindBadLiteral, KindMissingClauses},
+		},
+		{
+			desc:      "duplicate problem line",
+			input:     "p cnf 3 1\np cnf 3 1\n1 0\n",
+			wantCNF:   CNFFormula{NumVars: 3, Clauses: [][]int{{1}}},
+			wantLines: []int{2},
+			wantKinds: []ErrorKind{KindDuplicateProblem},
+		},
+		{
+			desc:      "invalid problem line",
+			input:     "c comment\n1 x 0\np cnf x 1\n1 0\n",
+			wantCNF:   CNFFormula{},
+			wantLines: []int{2, 3},
+			wantKinds: []ErrorKind{KindBadLiteral, KindBadProblem},
+		},
+		{
+			desc:      "clause before problem line",
+			input:     "1 0\np cnf 3 1\n1 0\n",
+			wantCNF:   CNFFormula{},
+			wantLines: []int{1},
+			wantKinds: []ErrorKind{KindClauseBeforeProblem},
+		},
+		{
+			desc:      "no problem line",
+			input:     "c comment\n",
+			wantCNF:   CNFFormula{},
+			wantLines: []int{1},
+			wantKinds: []ErrorKind{KindNoProblem},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotCNF, gotErr := ReadCNFWithOptions(strings.NewReader(tc.input), ReadCNFOptions{ContinueOnError: true})
+
+			var gotLines []int
+			var gotKinds []ErrorKind
+			if gotErr != nil {
+				errs, ok := gotErr.(ErrorList)
+				if !ok {
+					t.Fatalf("ReadCNFWithOptions(): want ErrorList, got %T", gotErr)
+				}
+				for _, e := range errs {
+					gotLines = append(gotLines, e.Line)
+					gotKinds = append(gotKinds, e.Kind)
+				}
+			}
+			if diff := cmp.Diff(tc.wantLines, gotLines); diff != "" {
+				t.Errorf("ReadCNFWithOptions(): error lines mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantKinds, gotKinds); diff != "" {
+				t.Errorf("ReadCNFWithOptions(): error kinds mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantCNF, gotCNF); diff != "" {
+				t.Errorf("ReadCNFWithOptions(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadBuilderWithOptions_continueOnError(t *testing.T) {
+	input := "c comment\np cnf 3 3\n1 x 0\n1 -2 0\n-3 0 c note\n0 1 0\n"
+	b := &testBuilder{}
+	cr := &commentRecorder{}
+
+	err := ReadBuilderWithOptions(strings.NewReader(input), cr, ReadOptions{ContinueOnError: true})
+
+	errs, ok := err.(ErrorList)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("ReadBuilderWithOptions(): want ErrorList of 2 errors, got %v", err)
+	}
+	if errs[0].Line != 3 || errs[1].Line != 6 {
+		t.Errorf("ReadBuilderWithOptions(): want errors on lines 3 and 6, got %d and %d", errs[0].Line, errs[1].Line)
+	}
+	if diff := cmp.Diff([]string{"c comment", "c note"}, cr.comments); diff != "" {
+		t.Errorf("ReadBuilderWithOptions(): comments mismatch (-want +got):\n%s", diff)
+	}
+	if err := ReadBuilderWithOptions(strings.NewReader(validCNF_manyComments), b, ReadOptions{ContinueOnError: true}); err != nil {
+		t.Errorf("ReadBuilderWithOptions(): want no error, got %s", err)
+	}
+}
+
+func TestReadCNFWithOptions_caseInsensitive(t *testing.T) {
+	want := CNFFormula{NumVars: 2, Clauses: [][]int{{1, -2}, {2}}}
+	testCases := []struct {
+		desc    string
+		input   string
+		opts    ReadCNFOptions
+		wantErr bool
+	}{
+		{
+			desc:    "uppercase rejected by default",
+			input:   "C comment\nP CNF 2 2\n1 -2 0\n2 0\n",
+			opts:    ReadCNFOptions{},
+			wantErr: true,
+		},
+		{
+			desc:    "uppercase",
+			input:   "C comment\nP CNF 2 2\n1 -2 0\n2 0\n",
+			opts:    ReadCNFOptions{CaseInsensitive: true},
+			wantErr: false,
+		},
+		{
+			desc:    "mixed case",
+			input:   "c comment\np Cnf 2 2\n1 -2 0 C note\n2 0\n",
+			opts:    ReadCNFOptions{CaseInsensitive: true},
+			wantErr: false,
+		},
+		{
+			desc:    "lowercase",
+			input:   "c comment\np cnf 2 2\n1 -2 0\n2 0\n",
+			opts:    ReadCNFOptions{CaseInsensitive: true},
+			wantErr: false,
+		},
+		{
+			desc:    "invalid problem type",
+			input:   "P WCNF 2 2\n1 -2 0\n2 0\n",
+			opts:    ReadCNFOptions{CaseInsensitive: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotErr := ReadCNFWithOptions(strings.NewReader(tc.input), tc.opts)
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadCNFWithOptions(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadCNFWithOptions(): want no error, got %s", gotErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("ReadCNFWithOptions(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadCNFWithOptions_multiLineClauses(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		input    string
+		want     CNFFormula
+		wantLine int
+		wantErr  bool
+	}{
+		{
+			desc:  "single line clauses",
+			input: "p cnf 3 2\n1 -2 0\n3 0\n",
+			want:  CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {3}}},
+		},
+		{
+			desc:  "multi-line clauses",
+			input: "p cnf 3 2\n1\n-2\n3 0\n-1\n0\n",
+			want:  CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2, 3}, {-1}}},
+		},
+		{
+			desc:  "comments between lines",
+			input: "p cnf 3 2\n1 -2\nc note\n3 0 c end\n\n-1 0\n",
+			want:  CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2, 3}, {-1}}},
+		},
+		{
+			desc:     "invalid literal",
+			input:    "p cnf 3 1\n1 -2\n3 x 0\n",
+			wantLine: 3,
+			wantErr:  true,
+		},
+		{
+			desc:     "unterminated last clause",
+			input:    "p cnf 3 2\n1 -2 0\n3\n",
+			wantLine: 3,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotErr := ReadCNFWithOptions(strings.NewReader(tc.input), ReadCNFOptions{MultiLineClauses: true})
+
+			if tc.wantErr && gotErr == nil {
+				t.Fatalf("ReadCNFWithOptions(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Fatalf("ReadCNFWithOptions(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ReadCNFWithOptions(): CNF mismatch (-want +got):\n%s", diff)
+			}
+			var pe *ParseError
+			if tc.wantErr && (!errors.As(gotErr, &pe) || pe.Line != tc.wantLine) {
+				t.Errorf("ReadCNFWithOptions(): want error on line %d, got %v", tc.wantLine, gotErr)
+			}
+		})
+	}
+}
+
+func TestReadBuilderWithOptions_caseInsensitive(t *testing.T) {
+	input := "C first\nP CNF 1 1\n1 0\n"
+	cr := &commentRecorder{}
+
+	if err := ReadBuilderWithOptions(strings.NewReader(input), cr, ReadOptions{CaseInsensitive: true}); err != nil {
+		t.Fatalf("ReadBuilderWithOptions(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff([]string{"C first"}, cr.comments); diff != "" {
+		t.Errorf("ReadBuilderWithOptions(): comments mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadCNF_lenientCapacity(t *testing.T) {
+	input := "p cnf 3 1000\n1 -2 0\n3 0\n-1 0\n"
+	opts := []ReadCNFOptions{
+		{IgnoreClauseCount: true},
+		{InferCounts: true},
+		{IgnoreClauseCount: true, Arena: true},
+	}
+
+	for _, o := range opts {
+		f, err := ReadCNFWithOptions(strings.NewReader(input), o)
+
+		if err != nil {
+			t.Fatalf("ReadCNFWithOptions(%+v): want no error, got %s", o, err)
+		}
+		if len(f.Clauses) != 3 || cap(f.Clauses) != 3 {
+			t.Errorf("ReadCNFWithOptions(%+v): want len and cap 3, got %d and %d", o, len(f.Clauses), cap(f.Clauses))
+		}
+	}
+}
+
+func TestReadCNFInferred(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		wantCNF CNFFormula
+		wantErr bool
+	}{
+		{
+			desc:  "correct counts",
+			input: validCNF_manyComments,
+			wantCNF: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{1, 2, 3}, {1, -2, 3}, {1, -3}, {-2, -3}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "wrong counts",
+			input: "p cnf 10 1\n1 -2 0\n-4 0\n",
+			wantCNF: CNFFormula{
+				NumVars: 4,
+				Clauses: [][]int{{1, -2}, {-4}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "no problem line",
+			input: "c comment\n1 -2 0\n-4 0\n",
+			wantCNF: CNFFormula{
+				NumVars: 4,
+				Clauses: [][]int{{1, -2}, {-4}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "empty input",
+			input:   "",
+			wantCNF: CNFFormula{Clauses: [][]int{}},
+			wantErr: false,
+		},
+		{
+			desc:  "bogus counts",
+			input: "p cnf ? ?\n1 2 0\n",
+			wantCNF: CNFFormula{
+				NumVars: 2,
+				Clauses: [][]int{{1, 2}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "missing counts",
+			input: "p cnf\n1 2 0\n",
+			wantCNF: CNFFormula{
+				NumVars: 2,
+				Clauses: [][]int{{1, 2}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "missing problem type",
+			input:   "p\n1 2 0\n",
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:    "invalid problem type",
+			input:   "p sat 3\n1 0\n",
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:    "invalid literal",
+			input:   "1 x 0\n",
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:    "zero in the middle of a clause",
+			input:   "1 0 2 0\n",
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotCNF, gotErr := ReadCNFInferred(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadCNFInferred(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadCNFInferred(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantCNF, gotCNF); diff != "" {
+				t.Errorf("ReadCNFInferred(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// randomCNF returns the DIMACS representation of a random formula with the
+// given number of variables, clauses, and literals per clause.
+func randomCNF(nVars, nClauses, width int) string {
+	rng := rand.New(rand.NewSource(42))
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "c random %d-CNF\np cnf %d %d\n", width, nVars, nClauses)
+	for i := 0; i < nClauses; i++ {
+		for j := 0; j < width; j++ {
+			l := rng.Intn(nVars) + 1
+			if rng.Intn(2) == 0 {
+				l = -l
+			}
+			fmt.Fprintf(&sb, "%d ", l)
+		}
+		sb.WriteString("0\n")
+	}
+	return sb.String()
+}
+
+func TestScanClause(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		line           string
+		want           []int
+		wantTerminated bool
+		wantErr        error
+	}{
+		{
+			desc:           "clause",
+			line:           "1 -2 3 0",
+			want:           []int{1, -2, 3},
+			wantTerminated: true,
+		},
+		{
+			desc:           "white space",
+			line:           " \t1\t -2  0 \r\n",
+			want:           []int{1, -2},
+			wantTerminated: true,
+		},
+		{
+			desc:           "unterminated",
+			line:           "4 5",
+			want:           []int{4, 5},
+			wantTerminated: false,
+		},
+		{
+			desc:           "empty clause",
+			line:           "0",
+			want:           []int{},
+			wantTerminated: true,
+		},
+		{
+			desc:           "empty line",
+			line:           "",
+			want:           []int{},
+			wantTerminated: false,
+		},
+		{
+			desc:    "zero before end of line",
+			line:    "1 0 2 0",
+			wantErr: ErrZeroLiteral,
+		},
+		{
+			desc:    "invalid literal",
+			line:    "1 x 0",
+			wantErr: strconv.ErrSyntax,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotTerminated, gotErr := ScanClause(tc.line, []int{})
+
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Fatalf("ScanClause(%q): want error %v, got %v", tc.line, tc.wantErr, gotErr)
+			}
+			if gotErr != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ScanClause(%q): literals mismatch (-want +got):\n%s", tc.line, diff)
+			}
+			if gotTerminated != tc.wantTerminated {
+				t.Errorf("ScanClause(%q): want terminated %t, got %t", tc.line, tc.wantTerminated, gotTerminated)
+			}
+		})
+	}
+}
+
+func TestScanClause_appendsToBuffer(t *testing.T) {
+	buf := make([]int, 0, 8)
+	buf = append(buf, 7)
+
+	got, _, err := ScanClause("1 2 0", buf)
+
+	if err != nil {
+		t.Fatalf("ScanClause(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff([]int{7, 1, 2}, got); diff != "" {
+		t.Errorf("ScanClause(): literals mismatch (-want +got):\n%s", diff)
+	}
+	if &got[0] != &buf[:1][0] {
+		t.Errorf("ScanClause(): want buffer to be reused")
+	}
+}
+
+func TestParseLiteral(t *testing.T) {
+	testCases := []struct {
+		tok    string
+		want   int
+		wantOK bool
+	}{
+		{tok: "0", want: 0, wantOK: true},
+		{tok: "7", want: 7, wantOK: true},
+		{tok: "-7", want: -7, wantOK: true},
+		{tok: "42", want: 42, wantOK: true},
+		{tok: "-42", want: -42, wantOK: true},
+		{tok: "123456", want: 123456, wantOK: true},
+		{tok: "-999999999", want: -999999999, wantOK: true},
+		{tok: "", want: 0, wantOK: false},
+		{tok: "-", want: 0, wantOK: false},
+		{tok: "+1", want: 0, wantOK: false},
+		{tok: "x", want: 0, wantOK: false},
+		{tok: "1x", want: 0, wantOK: false},
+		{tok: "-:", want: 0, wantOK: false},
+		{tok: "4/", want: 0, wantOK: false},
+	}
+
+	for _, tc := range testCases {
+		got, gotOK := parseLiteral(tc.tok)
+
+		if got != tc.want || gotOK != tc.wantOK {
+			t.Errorf("parseLiteral(%q): want (%d, %t), got (%d, %t)", tc.tok, tc.want, tc.wantOK, got, gotOK)
+		}
+	}
+}
+
+func TestParseLiteral_overflow(t *testing.T) {
+	toks := []string{
+		"999999999",
+		"-999999999",
+		"2147483648",
+		"99999999999",
+		"-999999999999999999",
+		"9223372036854775808",
+		"1000000000000000000",
+	}
+
+	for _, tok := range toks {
+		got, gotOK := parseLiteral(tok)
+		if !gotOK {
+			continue // delegated to strconv
+		}
+		want, err := strconv.Atoi(tok)
+		if err != nil || got != want {
+			t.Errorf("parseLiteral(%q): got %d, want strconv.Atoi result (%d, %v)", tok, got, want, err)
+		}
+	}
+	if _, ok := parseLiteral("-999999999999999999"); ok != (strconv.IntSize == 64) {
+		t.Errorf("parseLiteral(): want fast path for 18 digits %t, got %t", strconv.IntSize == 64, ok)
+	}
+}
+
+func TestReadCNF_literalOverflow(t *testing.T) {
+	_, err := ReadCNF(strings.NewReader("p cnf 3 1\n99999999999 0\n"))
+
+	if strconv.IntSize == 32 && err == nil {
+		t.Errorf("ReadCNF(): want error on 32-bit platforms, got nil")
+	}
+	if strconv.IntSize == 64 && err != nil {
+		t.Errorf("ReadCNF(): want no error on 64-bit platforms, got %s", err)
+	}
+}
+
+func BenchmarkParseLiteral(b *testing.B) {
+	inputs := []struct {
+		desc  string
+		nVars int
+	}{
+		{desc: "small", nVars: 50},
+		{desc: "large", nVars: 1000000},
+	}
+	for _, in := range inputs {
+		f, err := ReadCNF(strings.NewReader(randomCNF(in.nVars, 10000, 3)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		var toks [][]byte // as found in the lines read by the reader
+		for _, c := range f.Clauses {
+			for _, l := range c {
+				toks = append(toks, []byte(strconv.Itoa(l)))
+			}
+		}
+
+		b.Run(in.desc+"/fast", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, tok := range toks {
+					if _, ok := parseLiteral(tok); !ok {
+						b.Fatal(tok)
+					}
+				}
+			}
+		})
+		b.Run(in.desc+"/strconv", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, tok := range toks {
+					if _, err := strconv.Atoi(string(tok)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkReadBuilder_smallVars(b *testing.B) {
+	input := randomCNF(50, 400000, 3)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := ReadBuilder(strings.NewReader(input), &testBuilder{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadCNF_large(b *testing.B) {
+	input := randomCNF(100000, 400000, 3)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ReadCNF(strings.NewReader(input)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadBuilder_large(b *testing.B) {
+	input := randomCNF(100000, 400000, 3)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := ReadBuilder(strings.NewReader(input), &testBuilder{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestReadCNFArena(t *testing.T) {
+	inputs := []string{
+		"",
+		"p cnf 3 2\n1 2 3 0\n",
+		"p cnf 3 1\n1 2 3 0\n-1 0\n",
+		"p cnf 3 3\n0\n1 -2 0\n0\n",
+		validCNF_manyComments,
+		randomCNF(100, 400, 3),
+	}
+
+	for _, input := range inputs {
+		wantCNF, wantErr := ReadCNF(strings.NewReader(input))
+
+		gotCNF, gotErr := ReadCNFArena(strings.NewReader(input))
+
+		if !errorEqual(gotErr, wantErr) {
+			t.Errorf("ReadCNFArena(%.20q): want error %v, got %v", input, wantErr, gotErr)
+		}
+		if diff := cmp.Diff(wantCNF, gotCNF); diff != "" {
+			t.Errorf("ReadCNFArena(%.20q): CNF mismatch (-want +got):\n%s", input, diff)
+		}
+	}
+}
+
+func TestReadCNFArena_append(t *testing.T) {
+	f, err := ReadCNFArena(strings.NewReader("p cnf 3 2\n1 2 0\n-3 0\n"))
+	if err != nil {
+		t.Fatalf("ReadCNFArena(): want no error, got %s", err)
+	}
+
+	f.Clauses[0] = append(f.Clauses[0], 3)
+
+	want := [][]int{{1, 2, 3}, {-3}}
+	if diff := cmp.Diff(want, f.Clauses); diff != "" {
+		t.Errorf("ReadCNFArena(): clauses mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func BenchmarkReadCNFArena_large(b *testing.B) {
+	input := randomCNF(100000, 400000, 3)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ReadCNFArena(strings.NewReader(input)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadBuilder_wide(b *testing.B) {
+	input := randomCNF(50000, 20, 40000)
+	for _, hint := range []int{0, 40000} {
+		b.Run(fmt.Sprintf("hint=%d", hint), func(b *testing.B) {
+			opts := ReadOptions{ClauseWidthHint: hint}
+			b.SetBytes(int64(len(input)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := ReadBuilderWithOptions(strings.NewReader(input), &testBuilder{}, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestReadCNF_longLines(t *testing.T) {
+	nVars := 100000
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "c %s\np cnf %d 1\n", strings.Repeat("x", 200000), nVars)
+	want := CNFFormula{NumVars: nVars, Clauses: [][]int{make([]int, nVars)}}
+	for i := 1; i <= nVars; i++ {
+		fmt.Fprintf(&sb, "%d ", -i)
+		want.Clauses[0][i-1] = -i
+	}
+	sb.WriteString("0")
+
+	got, err := ReadCNF(strings.NewReader(sb.String()))
+
+	if err != nil {
+		t.Fatalf("ReadCNF(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ReadCNF(): CNF mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadBuilderWithOptions(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		input        string
+		opts         ReadOptions
+		wantComments []string
+		wantErr      bool
+	}{
+		{
+			desc:         "default prefix",
+			input:        "c comment 1\np cnf 1 1\nc comment 2\n1 0\n",
+			opts:         ReadOptions{},
+			wantComments: []string{"c comment 1", "c comment 2"},
+			wantErr:      false,
+		},
+		{
+			desc:         "custom prefix",
+			input:        "# comment 1\np cnf 1 1\n#comment 2\n1 0\n%\n# comment 3\n",
+			opts:         ReadOptions{CommentPrefix: "#"},
+			wantComments: []string{"# comment 1", "#comment 2", "# comment 3"},
+			wantErr:      false,
+		},
+		{
+			desc:         "custom prefix after clause",
+			input:        "p cnf 2 2\n1 -2 0 # note\n2 0 c not a comment\n",
+			opts:         ReadOptions{CommentPrefix: "#"},
+			wantComments: []string{"# note"},
+			wantErr:      true,
+		},
+		{
+			desc:         "multi-character prefix",
+			input:        "// comment\np cnf 1 1\n1 0\n",
+			opts:         ReadOptions{CommentPrefix: "//"},
+			wantComments: []string{"// comment"},
+			wantErr:      false,
+		},
+		{
+			desc:         "default prefix is not a comment",
+			input:        "* comment\np cnf 1 1\nc comment\n1 0\n",
+			opts:         ReadOptions{CommentPrefix: "*"},
+			wantComments: []string{"* comment"},
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cr := &commentRecorder{}
+
+			gotErr := ReadBuilderWithOptions(strings.NewReader(tc.input), cr, tc.opts)
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadBuilderWithOptions(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadBuilderWithOptions(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantComments, cr.comments); diff != "" {
+				t.Errorf("ReadBuilderWithOptions(): comments mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadBuilderWithOptions_percentCommentPrefix(t *testing.T) {
+	b := &CollectBuilder{CollectComments: true}
+	input := "% header\np cnf 2 1\n%comment\n1 2 0\n"
+
+	if err := ReadBuilderWithOptions(strings.NewReader(input), b, ReadOptions{CommentPrefix: "%"}); err != nil {
+		t.Fatalf("ReadBuilderWithOptions(): want no error, got %s", err)
+	}
+	got, err := b.Formula()
+	if err != nil {
+		t.Fatalf("Formula(): want no error, got %s", err)
+	}
+
+	want := CNFFormula{NumVars: 2, Clauses: [][]int{{1, 2}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ReadBuilderWithOptions(): CNF mismatch (-want +got):\n%s", diff)
+	}
+	wantComments := []string{"% header", "%comment"}
+	if diff := cmp.Diff(wantComments, b.Comments); diff != "" {
+		t.Errorf("ReadBuilderWithOptions(): comments mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadBuilderWithOptions_requireTerminator(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		input    string
+		opts     ReadOptions
+		wantLine int
+		wantErr  bool
+	}{
+		{
+			desc:    "terminated clauses",
+			input:   "p cnf 2 3\n1 -2 0\n0\n2 0 c note\n",
+			opts:    ReadOptions{RequireTerminator: true},
+			wantErr: false,
+		},
+		{
+			desc:    "unterminated clause accepted by default",
+			input:   "p cnf 2 2\n1 -2 0\n2\n",
+			opts:    ReadOptions{},
+			wantErr: false,
+		},
+		{
+			desc:     "unterminated clause",
+			input:    "p cnf 2 2\n1 -2 0\n2\n",
+			opts:     ReadOptions{RequireTerminator: true},
+			wantLine: 3,
+			wantErr:  true,
+		},
+		{
+			desc:     "truncated last line",
+			input:    "p cnf 2 2\n1 -2 0\n2 -1",
+			opts:     ReadOptions{RequireTerminator: true},
+			wantLine: 3,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotErr := ReadBuilderWithOptions(strings.NewReader(tc.input), &testBuilder{}, tc.opts)
+
+			if tc.wantErr && gotErr == nil {
+				t.Fatalf("ReadBuilderWithOptions(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Fatalf("ReadBuilderWithOptions(): want no error, got %s", gotErr)
+			}
+			if gotErr == nil {
+				return
+			}
+			var pe *ParseError
+			if !errors.As(gotErr, &pe) {
+				t.Fatalf("ReadBuilderWithOptions(): want *ParseError, got %T", gotErr)
+			}
+			if pe.Line != tc.wantLine {
+				t.Errorf("ReadBuilderWithOptions(): want error on line %d, got %d", tc.wantLine, pe.Line)
+			}
+			if !errors.Is(gotErr, ErrUnterminatedClause) {
+				t.Errorf("ReadBuilderWithOptions(): want error %q, got %q", ErrUnterminatedClause, gotErr)
+			}
+			if pe.Kind != KindUnterminatedClause {
+				t.Errorf("ReadBuilderWithOptions(): want kind %s, got %s", KindUnterminatedClause, pe.Kind)
+			}
+		})
+	}
+}
+
+func TestReadBuilder_trailingComment(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		input        string
+		wantClauses  [][]int
+		wantComments []string
+		wantErr      bool
+	}{
+		{
+			desc:         "comment after zero",
+			input:        "p cnf 2 2\n1 -2 0 c note\n2 0\tc another note \n",
+			wantClauses:  [][]int{{1, -2}, {2}},
+			wantComments: []string{"c note", "c another note"},
+			wantErr:      false,
+		},
+		{
+			desc:         "empty clause with comment",
+			input:        "p cnf 2 1\n0 c empty\n",
+			wantClauses:  [][]int{{}},
+			wantComments: []string{"c empty"},
+			wantErr:      false,
+		},
+		{
+			desc:    "comment before zero",
+			input:   "p cnf 2 1\n1 c -2 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "literal after zero",
+			input:   "p cnf 2 1\n1 0 2 0 c note\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			b := &CollectBuilder{CollectComments: true}
+
+			gotErr := ReadBuilder(strings.NewReader(tc.input), b)
+
+			if tc.wantErr {
+				if gotErr == nil {
+					t.Errorf("ReadBuilder(): want error, got nil")
+				}
+				return
+			}
+			if gotErr != nil {
+				t.Fatalf("ReadBuilder(): want no error, got %s", gotErr)
+			}
+			f, err := b.Formula()
+			if err != nil {
+				t.Fatalf("Formula(): want no error, got %s", err)
+			}
+			if diff := cmp.Diff(tc.wantClauses, f.Clauses); diff != "" {
+				t.Errorf("ReadBuilder(): clauses mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantComments, b.Comments); diff != "" {
+				t.Errorf("ReadBuilder(): comments mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
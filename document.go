@@ -0,0 +1,123 @@
+package dimacs
+
+import (
+	"bufio"
+	"context"
+	"io"
+)
+
+// CNFDocument represents a DIMACS CNF file with its comments, which allows to
+// modify a formula without losing the comments when writing it back.
+type CNFDocument struct {
+	NumVars int
+
+	// Header holds the comment lines that precede the problem line.
+	Header []string
+
+	// Records holds the clauses and comment lines that follow the problem
+	// line, in order.
+	Records []CNFRecord
+}
+
+// CNFRecord is either a comment line or a clause of a CNFDocument.
+type CNFRecord struct {
+	// Comment is the comment line, including its "c" prefix. It is empty if
+	// the record is a clause.
+	Comment string
+
+	// Clause is the clause of the record if it is not a comment.
+	Clause []int
+}
+
+// IsComment returns true if the record is a comment line.
+func (r CNFRecord) IsComment() bool {
+	return r.Comment != ""
+}
+
+// Formula returns the formula made of the clauses of the document. The
+// clauses are shared with the document.
+func (d CNFDocument) Formula() CNFFormula {
+	f := CNFFormula{NumVars: d.NumVars, Clauses: [][]int{}}
+	for _, r := range d.Records {
+		if !r.IsComment() {
+			f.Clauses = append(f.Clauses, r.Clause)
+		}
+	}
+	return f
+}
+
+// ReadCNFDocument is like ReadCNF but also keeps the comment lines and their
+// position relative to the problem line and the clauses.
+func ReadCNFDocument(r io.Reader) (CNFDocument, error) {
+	b := documentBuilder{doc: CNFDocument{Header: []string{}, Records: []CNFRecord{}}}
+	lines, err := readBuilder(context.Background(), r, &b, ReadOptions{})
+	if err != nil {
+		return CNFDocument{}, err
+	}
+	f, err := b.formula()
+	if err != nil {
+		return CNFDocument{}, newParseError(lines, "", err)
+	}
+	b.doc.NumVars = f.NumVars
+	return b.doc, nil
+}
+
+type documentBuilder struct {
+	cnfBuilder
+	doc CNFDocument
+}
+
+func (b *documentBuilder) Clause(tmp []int) error {
+	if err := b.cnfBuilder.Clause(tmp); err != nil {
+		return err
+	}
+	clause := b.cnf.Clauses[len(b.cnf.Clauses)-1]
+	b.doc.Records = append(b.doc.Records, CNFRecord{Clause: clause})
+	return nil
+}
+
+func (b *documentBuilder) Comment(line string) error {
+	if b.cnf == nil {
+		b.doc.Header = append(b.doc.Header, line)
+	} else {
+		b.doc.Records = append(b.doc.Records, CNFRecord{Comment: line})
+	}
+	return nil
+}
+
+// WriteCNFDocument writes the given document to w in the DIMACS CNF format:
+// the header comments, a problem line declaring the clauses of the document,
+// and the clauses and comments in order. Comment lines are written as is and
+// must thus start with "c".
+func WriteCNFDocument(w io.Writer, d CNFDocument) error {
+	nClauses := 0
+	for _, r := range d.Records {
+		if !r.IsComment() {
+			nClauses++
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, 0, 64)
+	for _, c := range d.Header {
+		buf = append(append(buf[:0], c...), '\n')
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+	}
+	buf = appendProblem(buf[:0], "cnf", d.NumVars, nClauses)
+	if _, err := bw.Write(buf); err != nil {
+		return err
+	}
+	for _, r := range d.Records {
+		if r.IsComment() {
+			buf = append(append(buf[:0], r.Comment...), '\n')
+		} else {
+			buf = appendClause(buf[:0], r.Clause)
+		}
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,97 @@
+package dimacs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const validCNFDocument = `c header 1
+c header 2
+p cnf 3 3
+c before first clause
+1 -2 0
+2 3 0
+c between clauses
+0
+c trailing
+`
+
+func TestReadCNFDocument(t *testing.T) {
+	got, err := ReadCNFDocument(strings.NewReader(validCNFDocument))
+
+	if err != nil {
+		t.Fatalf("ReadCNFDocument(): want no error, got %s", err)
+	}
+	want := CNFDocument{
+		NumVars: 3,
+		Header:  []string{"c header 1", "c header 2"},
+		Records: []CNFRecord{
+			{Comment: "c before first clause"},
+			{Clause: []int{1, -2}},
+			{Clause: []int{2, 3}},
+			{Comment: "c between clauses"},
+			{Clause: []int{}},
+			{Comment: "c trailing"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ReadCNFDocument(): document mismatch (-want +got):\n%s", diff)
+	}
+	wantCNF := CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {2, 3}, {}}}
+	if diff := cmp.Diff(wantCNF, got.Formula()); diff != "" {
+		t.Errorf("Formula(): CNF mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadCNFDocument_errors(t *testing.T) {
+	inputs := []string{
+		"",
+		"p cnf 3 2\n1 0\n",
+		"p cnf 3 1\n1 0\n2 0\n",
+		"1 0\np cnf 3 1\n",
+	}
+
+	for _, input := range inputs {
+		if _, err := ReadCNFDocument(strings.NewReader(input)); err == nil {
+			t.Errorf("ReadCNFDocument(%q): want error, got nil", input)
+		}
+	}
+}
+
+func TestWriteCNFDocument_roundTrip(t *testing.T) {
+	doc, err := ReadCNFDocument(strings.NewReader(validCNFDocument))
+	if err != nil {
+		t.Fatalf("ReadCNFDocument(): want no error, got %s", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WriteCNFDocument(buf, doc); err != nil {
+		t.Fatalf("WriteCNFDocument(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff(validCNFDocument, buf.String()); diff != "" {
+		t.Errorf("WriteCNFDocument(): output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteCNFDocument_modified(t *testing.T) {
+	doc, err := ReadCNFDocument(strings.NewReader(validCNFDocument))
+	if err != nil {
+		t.Fatalf("ReadCNFDocument(): want no error, got %s", err)
+	}
+	doc.Records[2].Clause[0] = -2
+	doc.Records = append(doc.Records[:4], doc.Records[5:]...) // remove empty clause
+
+	buf := &bytes.Buffer{}
+	if err := WriteCNFDocument(buf, doc); err != nil {
+		t.Fatalf("WriteCNFDocument(): want no error, got %s", err)
+	}
+
+	want := "c header 1\nc header 2\np cnf 3 2\nc before first clause\n1 -2 0\n-2 3 0\nc between clauses\nc trailing\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteCNFDocument(): output mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,70 @@
+package dimacs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// ProofStep is a step of a clausal proof such as a DRAT proof. A step either
+// adds a clause to the formula or, if Delete is true, deletes a clause from it.
+// The empty clause is represented by an empty Literals slice.
+type ProofStep struct {
+	Delete   bool
+	Literals []int
+}
+
+// ReadDRAT parses a proof in the textual DRAT format from the given reader and
+// returns its steps in order. Addition lines are clause lines (e.g. "1 -2 0")
+// while deletion lines are clause lines prefixed with "d" (e.g. "d 1 -2 0").
+// Each line must be terminated by a 0. Comment lines (starting with "c") are
+// ignored. The binary DRAT format is not supported.
+func ReadDRAT(r io.Reader) ([]ProofStep, error) {
+	rd := Reader{}
+	rd.Reset(r)
+	steps := []ProofStep{}
+	var buf []int
+
+	for n := 1; ; n++ {
+		line, err := rd.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n == 1 {
+			line = bytes.TrimPrefix(line, []byte(utf8BOM))
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == 'c' {
+			continue
+		}
+
+		step := ProofStep{}
+		lits := line
+		if line[0] == 'd' {
+			step.Delete = true
+			lits = line[1:]
+			if len(lits) != 0 && lits[0] != ' ' && lits[0] != '\t' {
+				err := fmt.Errorf("invalid deletion line %q", line)
+				return nil, newParseError(n, string(line), err)
+			}
+		}
+
+		var terminated bool
+		buf, terminated, err = parseClause(lits, buf[:0])
+		if err != nil {
+			return nil, newParseError(n, string(line), err)
+		}
+		if !terminated {
+			err := fmt.Errorf("%w: %q", ErrUnterminatedClause, line)
+			return nil, newParseError(n, string(line), err)
+		}
+
+		step.Literals = make([]int, len(buf))
+		copy(step.Literals, buf)
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
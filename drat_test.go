@@ -0,0 +1,106 @@
+package dimacs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const validDRAT = `c a proof
+1 -2 0
+d 1 -2 0
+
+d -3 0
+-1 0
+0
+`
+
+func TestReadDRAT(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		input     string
+		wantSteps []ProofStep
+		wantErr   bool
+	}{
+		{
+			desc:      "byte order mark",
+			input:     "\ufeffd 1 -2 0\n",
+			wantSteps: []ProofStep{{Delete: true, Literals: []int{1, -2}}},
+		},
+		{
+			desc:  "valid proof",
+			input: validDRAT,
+			wantSteps: []ProofStep{
+				{Delete: false, Literals: []int{1, -2}},
+				{Delete: true, Literals: []int{1, -2}},
+				{Delete: true, Literals: []int{-3}},
+				{Delete: false, Literals: []int{-1}},
+				{Delete: false, Literals: []int{}},
+			},
+		},
+		{
+			desc:      "empty proof",
+			input:     "",
+			wantSteps: []ProofStep{},
+		},
+		{
+			desc:    "zero before end of line",
+			input:   "1 0 2 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "not terminated",
+			input:   "1 2\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid literal",
+			input:   "d 1 x 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid deletion prefix",
+			input:   "d1 2 0\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotSteps, gotErr := ReadDRAT(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadDRAT(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadDRAT(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantSteps, gotSteps); diff != "" {
+				t.Errorf("ReadDRAT(): steps mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadDRAT_longLine(t *testing.T) {
+	const nVars = 20000
+	sb := strings.Builder{}
+	sb.WriteString("d")
+	want := ProofStep{Delete: true}
+	for v := 1; v <= nVars; v++ {
+		fmt.Fprintf(&sb, " %d", -v)
+		want.Literals = append(want.Literals, -v)
+	}
+	sb.WriteString(" 0\n")
+
+	got, err := ReadDRAT(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatalf("ReadDRAT(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff([]ProofStep{want}, got); diff != "" {
+		t.Errorf("ReadDRAT(): steps mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,278 @@
+package dimacs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Sentinel errors returned (wrapped) by the parsing functions of this package.
+// They are meant to be tested with errors.Is, the wrapping error carrying a
+// human-readable description of the failure.
+var (
+	// ErrNoProblemLine indicates that the input does not have a problem line.
+	ErrNoProblemLine = errors.New("missing problem line")
+
+	// ErrDuplicateProblem indicates that the input has more than one problem
+	// line.
+	ErrDuplicateProblem = errors.New("duplicate problem line")
+
+	// ErrInvalidProblemLine indicates that the problem line is malformed (e.g.
+	// wrong number of fields or negative counts).
+	ErrInvalidProblemLine = errors.New("invalid problem line")
+
+	// ErrInvalidProblemType indicates that the problem line declares a problem
+	// type that is not supported (e.g. "p sat" instead of "p cnf").
+	ErrInvalidProblemType = errors.New("invalid problem type")
+
+	// ErrClauseBeforeProblem indicates that a clause line appears before the
+	// problem line.
+	ErrClauseBeforeProblem = errors.New("clause found before problem line")
+
+	// ErrTooManyClauses indicates that the input has more clauses than what
+	// was declared in the problem line.
+	ErrTooManyClauses = errors.New("too many clauses")
+
+	// ErrMissingClauses indicates that the input has fewer clauses than what
+	// was declared in the problem line.
+	ErrMissingClauses = errors.New("missing clauses")
+
+	// ErrZeroLiteral indicates that a clause line contains a 0 that is not its
+	// last token.
+	ErrZeroLiteral = errors.New("zero found before end of clause line")
+
+	// ErrDuplicateLiteral indicates that a literal appears more than once in
+	// the same clause.
+	ErrDuplicateLiteral = errors.New("duplicate literal in clause")
+
+	// ErrTautology indicates that a clause contains both a literal and its
+	// negation.
+	ErrTautology = errors.New("tautological clause")
+
+	// ErrVarOutOfRange indicates that a clause contains a variable greater
+	// than the number of variables declared in the problem line.
+	ErrVarOutOfRange = errors.New("variable out of range")
+
+	// ErrUnterminatedClause indicates that a clause line does not end with a
+	// 0 while ReadOptions.RequireTerminator is set.
+	ErrUnterminatedClause = errors.New("clause not terminated by 0")
+)
+
+// ClauseCountError reports that the number of clauses in the input does not
+// match the number of clauses declared in the problem line. It matches
+// ErrTooManyClauses or ErrMissingClauses (depending on TooMany) when tested
+// with errors.Is.
+type ClauseCountError struct {
+	// Declared is the number of clauses declared in the problem line.
+	Declared int
+
+	// Actual is the number of clauses read. When TooMany is true, reading
+	// stops at the first extra clause and Actual is thus Declared+1.
+	Actual int
+
+	// TooMany is true if the input has more clauses than declared and false
+	// if it has fewer.
+	TooMany bool
+}
+
+func (e *ClauseCountError) Error() string {
+	if e.TooMany {
+		return fmt.Sprintf("%s: expected %d", ErrTooManyClauses, e.Declared)
+	}
+	return fmt.Sprintf("%s: expected %d, got %d", ErrMissingClauses, e.Declared, e.Actual)
+}
+
+// Is reports whether target is the sentinel error corresponding to e.
+func (e *ClauseCountError) Is(target error) bool {
+	if e.TooMany {
+		return target == ErrTooManyClauses
+	}
+	return target == ErrMissingClauses
+}
+
+// ErrorKind classifies parsing errors.
+type ErrorKind int
+
+const (
+	// KindOther is the kind of errors that do not fall in any other kind
+	// (e.g. errors returned by custom builders).
+	KindOther ErrorKind = iota
+
+	// KindBadProblem is the kind of errors caused by a malformed problem line.
+	KindBadProblem
+
+	// KindInvalidProblemType is the kind of errors caused by an unsupported
+	// problem type.
+	KindInvalidProblemType
+
+	// KindDuplicateProblem is the kind of errors caused by a second problem
+	// line.
+	KindDuplicateProblem
+
+	// KindNoProblem is the kind of errors caused by a missing problem line.
+	KindNoProblem
+
+	// KindBadLiteral is the kind of errors caused by a non-integer literal.
+	KindBadLiteral
+
+	// KindZeroMidClause is the kind of errors caused by a 0 that is not the
+	// last token of a clause line.
+	KindZeroMidClause
+
+	// KindClauseBeforeProblem is the kind of errors caused by a clause that
+	// appears before the problem line.
+	KindClauseBeforeProblem
+
+	// KindTooManyClauses is the kind of errors caused by more clauses than
+	// declared in the problem line.
+	KindTooManyClauses
+
+	// KindMissingClauses is the kind of errors caused by fewer clauses than
+	// declared in the problem line.
+	KindMissingClauses
+
+	// KindDuplicateLiteral is the kind of errors caused by a literal that
+	// appears more than once in the same clause.
+	KindDuplicateLiteral
+
+	// KindTautology is the kind of errors caused by a clause that contains
+	// both a literal and its negation.
+	KindTautology
+
+	// KindVarOutOfRange is the kind of errors caused by a variable greater
+	// than the number of variables declared in the problem line.
+	KindVarOutOfRange
+
+	// KindUnterminatedClause is the kind of errors caused by a clause line
+	// that does not end with a 0 when one is required.
+	KindUnterminatedClause
+)
+
+var errorKindNames = [...]string{
+	KindOther:               "Other",
+	KindBadProblem:          "BadProblem",
+	KindInvalidProblemType:  "InvalidProblemType",
+	KindDuplicateProblem:    "DuplicateProblem",
+	KindNoProblem:           "NoProblem",
+	KindBadLiteral:          "BadLiteral",
+	KindZeroMidClause:       "ZeroMidClause",
+	KindClauseBeforeProblem: "ClauseBeforeProblem",
+	KindTooManyClauses:      "TooManyClauses",
+	KindMissingClauses:      "MissingClauses",
+	KindDuplicateLiteral:    "DuplicateLiteral",
+	KindTautology:           "Tautology",
+	KindVarOutOfRange:       "VarOutOfRange",
+	KindUnterminatedClause:  "UnterminatedClause",
+}
+
+func (k ErrorKind) String() string {
+	if k < 0 || int(k) >= len(errorKindNames) {
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+	return errorKindNames[k]
+}
+
+// sentinelKinds maps the sentinel errors to their kind.
+var sentinelKinds = []struct {
+	err  error
+	kind ErrorKind
+}{
+	{ErrInvalidProblemLine, KindBadProblem},
+	{ErrInvalidProblemType, KindInvalidProblemType},
+	{ErrDuplicateProblem, KindDuplicateProblem},
+	{ErrNoProblemLine, KindNoProblem},
+	{ErrZeroLiteral, KindZeroMidClause},
+	{ErrClauseBeforeProblem, KindClauseBeforeProblem},
+	{ErrTooManyClauses, KindTooManyClauses},
+	{ErrMissingClauses, KindMissingClauses},
+	{ErrDuplicateLiteral, KindDuplicateLiteral},
+	{ErrTautology, KindTautology},
+	{ErrVarOutOfRange, KindVarOutOfRange},
+	{ErrUnterminatedClause, KindUnterminatedClause},
+}
+
+// ParseError describes an error that occurred while parsing a DIMACS file. The
+// underlying error can be recovered with errors.Is and errors.As.
+//
+// All the readers of this package, including the ones of the DIMACS-derived
+// formats (e.g. ReadGraph, ReadOPB or ReadSolution), report parsing errors as
+// *ParseError, wrapping the closest sentinel error (e.g. a graph edge found
+// before the problem line wraps ErrClauseBeforeProblem).
+type ParseError struct {
+	// Line is the 1-based number of the line where the error occurred. Errors
+	// detected at the end of the input (e.g. missing clauses) are reported on
+	// the last line.
+	Line int
+
+	// Kind classifies the error.
+	Kind ErrorKind
+
+	// Text is the raw content of the offending line, if any.
+	Text string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the message of the underlying error prefixed by the line
+// number (e.g. "line 3: ..."), or without prefix if the line is unknown.
+func (e *ParseError) Error() string {
+	if e.Line <= 0 {
+		return e.Err.Error()
+	}
+	return "line " + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorList is a list of parse errors in order of appearance in the input. It
+// is returned by the reading functions when errors are accumulated rather than
+// reported one at a time (see ReadOptions.ContinueOnError).
+type ErrorList []*ParseError
+
+// Error returns the message of the first error and the number of other errors.
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
+// Unwrap returns the errors of the list so that errors.Is and errors.As can
+// match any of them (starting with Go 1.20).
+func (l ErrorList) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, e := range l {
+		errs[i] = e
+	}
+	return errs
+}
+
+// withLine sets the line of the error and returns it.
+func (e *ParseError) withLine(line int) *ParseError {
+	e.Line = line
+	return e
+}
+
+// newParseError returns a *ParseError wrapping err whose kind is inferred from
+// the sentinel errors err wraps, if any. Errors wrapping a *strconv.NumError
+// are considered to be caused by an invalid literal.
+func newParseError(line int, text string, err error) *ParseError {
+	kind := KindOther
+	for _, sk := range sentinelKinds {
+		if errors.Is(err, sk.err) {
+			kind = sk.kind
+			break
+		}
+	}
+	var numErr *strconv.NumError
+	if kind == KindOther && errors.As(err, &numErr) {
+		kind = KindBadLiteral
+	}
+	return &ParseError{Line: line, Kind: kind, Text: text, Err: err}
+}
@@ -0,0 +1,510 @@
+package dimacs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestReadCNF_sentinelErrors(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		wantErr error
+	}{
+		{
+			desc:    "no problem line",
+			input:   "c no problem or clause",
+			wantErr: ErrNoProblemLine,
+		},
+		{
+			desc:    "duplicate problem lines",
+			input:   "p cnf 3 4\np cnf 3 4",
+			wantErr: ErrDuplicateProblem,
+		},
+		{
+			desc:    "invalid problem line",
+			input:   "p cnf 3",
+			wantErr: ErrInvalidProblemLine,
+		},
+		{
+			desc:    "negative number of variables",
+			input:   "p cnf -1 3",
+			wantErr: ErrInvalidProblemLine,
+		},
+		{
+			desc:    "invalid problem type",
+			input:   "p foo 3 4",
+			wantErr: ErrInvalidProblemType,
+		},
+		{
+			desc:    "clause before problem line",
+			input:   "1 2 3 0\np cnf 3 4",
+			wantErr: ErrClauseBeforeProblem,
+		},
+		{
+			desc:    "too many clauses",
+			input:   "p cnf 3 1\n1 2 3 0\n2 3 0",
+			wantErr: ErrTooManyClauses,
+		},
+		{
+			desc:    "missing clauses",
+			input:   "p cnf 3 2\n1 2 3 0",
+			wantErr: ErrMissingClauses,
+		},
+		{
+			desc:    "zero literal",
+			input:   "p cnf 3 1\n1 0 3 0",
+			wantErr: ErrZeroLiteral,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, gotErr := ReadCNF(strings.NewReader(tc.input))
+
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("ReadCNF(): want error %q, got %v", tc.wantErr, gotErr)
+			}
+		})
+	}
+}
+
+func TestReadCNF_clauseCountError(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		wantErr *ClauseCountError
+	}{
+		{
+			desc:    "missing clauses",
+			input:   "p cnf 3 3\n1 2 3 0\n-1 0\n",
+			wantErr: &ClauseCountError{Declared: 3, Actual: 2},
+		},
+		{
+			desc:    "too many clauses",
+			input:   "p cnf 3 1\n1 2 3 0\n2 3 0\n",
+			wantErr: &ClauseCountError{Declared: 1, Actual: 2, TooMany: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, gotErr := ReadCNF(strings.NewReader(tc.input))
+
+			var ccErr *ClauseCountError
+			if !errors.As(gotErr, &ccErr) {
+				t.Fatalf("ReadCNF(): want *ClauseCountError, got %v", gotErr)
+			}
+			if *ccErr != *tc.wantErr {
+				t.Errorf("ReadCNF(): want error %+v, got %+v", *tc.wantErr, *ccErr)
+			}
+		})
+	}
+}
+
+func TestReadCNF_parseError(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		input    string
+		wantLine int
+		wantKind ErrorKind
+		wantText string
+	}{
+		{
+			desc:     "no problem line",
+			input:    "c comment\nc comment\n",
+			wantLine: 2,
+			wantKind: KindNoProblem,
+			wantText: "",
+		},
+		{
+			desc:     "bad problem line",
+			input:    "c comment\np cnf 3\n",
+			wantLine: 2,
+			wantKind: KindBadProblem,
+			wantText: "p cnf 3",
+		},
+		{
+			desc:     "non-integer variable count",
+			input:    "p cnf x 3\n",
+			wantLine: 1,
+			wantKind: KindBadProblem,
+			wantText: "p cnf x 3",
+		},
+		{
+			desc:     "invalid problem type",
+			input:    "p sat 3 1\n",
+			wantLine: 1,
+			wantKind: KindInvalidProblemType,
+			wantText: "p sat 3 1",
+		},
+		{
+			desc:     "duplicate problem line",
+			input:    "p cnf 3 1\np cnf 3 1\n",
+			wantLine: 2,
+			wantKind: KindDuplicateProblem,
+			wantText: "p cnf 3 1",
+		},
+		{
+			desc:     "bad literal",
+			input:    "p cnf 3 1\n1 x 0\n",
+			wantLine: 2,
+			wantKind: KindBadLiteral,
+			wantText: "1 x 0",
+		},
+		{
+			desc:     "zero mid clause",
+			input:    "p cnf 3 1\n\n1 0 2 0\n",
+			wantLine: 3,
+			wantKind: KindZeroMidClause,
+			wantText: "1 0 2 0",
+		},
+		{
+			desc:     "clause before problem",
+			input:    "1 2 0\np cnf 3 1\n",
+			wantLine: 1,
+			wantKind: KindClauseBeforeProblem,
+			wantText: "1 2 0",
+		},
+		{
+			desc:     "too many clauses",
+			input:    "p cnf 3 1\n1 0\n2 0\n",
+			wantLine: 3,
+			wantKind: KindTooManyClauses,
+			wantText: "2 0",
+		},
+		{
+			desc:     "missing clauses",
+			input:    "p cnf 3 2\n1 0\nc comment\n",
+			wantLine: 3,
+			wantKind: KindMissingClauses,
+			wantText: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, gotErr := ReadCNF(strings.NewReader(tc.input))
+
+			var pErr *ParseError
+			if !errors.As(gotErr, &pErr) {
+				t.Fatalf("ReadCNF(): want *ParseError, got %v", gotErr)
+			}
+			if pErr.Line != tc.wantLine {
+				t.Errorf("ReadCNF(): want line %d, got %d", tc.wantLine, pErr.Line)
+			}
+			if pErr.Kind != tc.wantKind {
+				t.Errorf("ReadCNF(): want kind %s, got %s", tc.wantKind, pErr.Kind)
+			}
+			if pErr.Text != tc.wantText {
+				t.Errorf("ReadCNF(): want text %q, got %q", tc.wantText, pErr.Text)
+			}
+		})
+	}
+}
+
+func TestReadCNF_problemCountError(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		wantMsg string
+	}{
+		{
+			desc:    "variable count",
+			input:   "p cnf x 3\n",
+			wantMsg: `line 1: problem line has non-integer variable count "x"`,
+		},
+		{
+			desc:    "clause count",
+			input:   "c comment\np cnf 3 1.5\n",
+			wantMsg: `line 2: problem line has non-integer clause count "1.5"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, gotErr := ReadCNF(strings.NewReader(tc.input))
+
+			if gotErr == nil {
+				t.Fatalf("ReadCNF(): want error, got nil")
+			}
+			if got := gotErr.Error(); got != tc.wantMsg {
+				t.Errorf("ReadCNF(): want message %q, got %q", tc.wantMsg, got)
+			}
+			if !errors.Is(gotErr, ErrInvalidProblemLine) {
+				t.Errorf("ReadCNF(): want error wrapping %s, got %s", ErrInvalidProblemLine, gotErr)
+			}
+			var numErr *strconv.NumError
+			if !errors.As(gotErr, &numErr) {
+				t.Errorf("ReadCNF(): want error wrapping *strconv.NumError, got %s", gotErr)
+			}
+		})
+	}
+}
+
+func TestParseError_Error(t *testing.T) {
+	testCases := []struct {
+		desc string
+		err  *ParseError
+		want string
+	}{
+		{
+			desc: "with line",
+			err:  &ParseError{Line: 3, Err: ErrZeroLiteral},
+			want: "line 3: " + ErrZeroLiteral.Error(),
+		},
+		{
+			desc: "unknown line",
+			err:  &ParseError{Err: ErrNoProblemLine},
+			want: ErrNoProblemLine.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := tc.err.Error(); got != tc.want {
+				t.Errorf("Error(): want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestReadCNFWithOptions_literalErrors(t *testing.T) {
+	input := "p cnf 3 2\n1 2 0\n3 -1 3 1 0\n"
+
+	testCases := []struct {
+		desc     string
+		opts     ReadCNFOptions
+		wantErr  error
+		wantKind ErrorKind
+	}{
+		{
+			desc:     "duplicate literal",
+			opts:     ReadCNFOptions{RejectDuplicateLiterals: true},
+			wantErr:  ErrDuplicateLiteral,
+			wantKind: KindDuplicateLiteral,
+		},
+		{
+			desc:     "tautology",
+			opts:     ReadCNFOptions{RejectTautologies: true},
+			wantErr:  ErrTautology,
+			wantKind: KindTautology,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, gotErr := ReadCNFWithOptions(strings.NewReader(input), tc.opts)
+
+			var pErr *ParseError
+			if !errors.As(gotErr, &pErr) {
+				t.Fatalf("ReadCNFWithOptions(): want *ParseError, got %v", gotErr)
+			}
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("ReadCNFWithOptions(): want error wrapping %s, got %s", tc.wantErr, gotErr)
+			}
+			if pErr.Line != 3 {
+				t.Errorf("ReadCNFWithOptions(): want line 3, got %d", pErr.Line)
+			}
+			if pErr.Kind != tc.wantKind {
+				t.Errorf("ReadCNFWithOptions(): want kind %s, got %s", tc.wantKind, pErr.Kind)
+			}
+		})
+	}
+}
+
+func TestReadBuilder_builderErrorKind(t *testing.T) {
+	builderErr := errors.New("builder error")
+
+	gotErr := ReadBuilder(strings.NewReader(validCNF_noComments), &testBuilder{ClauseErr: builderErr})
+
+	var pErr *ParseError
+	if !errors.As(gotErr, &pErr) {
+		t.Fatalf("ReadBuilder(): want *ParseError, got %v", gotErr)
+	}
+	if pErr.Kind != KindOther {
+		t.Errorf("ReadBuilder(): want kind %s, got %s", KindOther, pErr.Kind)
+	}
+	if !errors.Is(gotErr, builderErr) {
+		t.Errorf("ReadBuilder(): want error wrapping %s, got %s", builderErr, gotErr)
+	}
+}
+
+func TestErrorList(t *testing.T) {
+	first := &ParseError{Line: 2, Kind: KindBadLiteral, Err: errors.New("first")}
+	second := &ParseError{Line: 5, Kind: KindOther, Err: ErrTautology}
+	testCases := []struct {
+		desc string
+		list ErrorList
+		want string
+	}{
+		{desc: "empty", list: ErrorList{}, want: "no errors"},
+		{desc: "single error", list: ErrorList{first}, want: "line 2: first"},
+		{desc: "several errors", list: ErrorList{first, second, second}, want: "line 2: first (and 2 more errors)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := tc.list.Error(); got != tc.want {
+				t.Errorf("Error(): want %q, got %q", tc.want, got)
+			}
+		})
+	}
+
+	unwrapped := ErrorList{first, second}.Unwrap()
+	if len(unwrapped) != 2 || unwrapped[0] != first || unwrapped[1] != second {
+		t.Errorf("Unwrap(): want the errors of the list, got %v", unwrapped)
+	}
+}
+
+func TestParseError_otherFormats(t *testing.T) {
+	readGraph := func(s string) error { _, err := ReadGraph(strings.NewReader(s)); return err }
+	readSAT := func(s string) error { _, err := ReadSAT(strings.NewReader(s)); return err }
+	readDRAT := func(s string) error { _, err := ReadDRAT(strings.NewReader(s)); return err }
+	readLRAT := func(s string) error { _, err := ReadLRAT(strings.NewReader(s)); return err }
+	readSolution := func(s string) error { _, err := ReadSolution(strings.NewReader(s)); return err }
+	readOPB := func(s string) error { _, err := ReadOPB(strings.NewReader(s)); return err }
+
+	testCases := []struct {
+		desc     string
+		read     func(string) error
+		input    string
+		wantIs   error // sentinel error wrapped by the error, if any
+		wantKind ErrorKind
+		wantLine int
+	}{
+		{
+			desc:     "graph edge before problem line",
+			read:     readGraph,
+			input:    "e 1 2\np edge 2 1\n",
+			wantIs:   ErrClauseBeforeProblem,
+			wantKind: KindClauseBeforeProblem,
+			wantLine: 1,
+		},
+		{
+			desc:     "graph too many edges",
+			read:     readGraph,
+			input:    "p edge 2 1\ne 1 2\ne 2 1\n",
+			wantIs:   ErrTooManyClauses,
+			wantKind: KindTooManyClauses,
+			wantLine: 3,
+		},
+		{
+			desc:     "graph missing edges",
+			read:     readGraph,
+			input:    "p edge 2 2\ne 1 2\n",
+			wantIs:   ErrMissingClauses,
+			wantKind: KindMissingClauses,
+			wantLine: 2,
+		},
+		{
+			desc:     "graph node out of range",
+			read:     readGraph,
+			input:    "p edge 2 1\ne 1 3\n",
+			wantIs:   ErrVarOutOfRange,
+			wantKind: KindVarOutOfRange,
+			wantLine: 2,
+		},
+		{
+			desc:     "sat formula before problem line",
+			read:     readSAT,
+			input:    "(1)\np sat 1\n",
+			wantIs:   ErrClauseBeforeProblem,
+			wantKind: KindClauseBeforeProblem,
+			wantLine: 1,
+		},
+		{
+			desc:     "sat variable out of range",
+			read:     readSAT,
+			input:    "p sat 1\n+(1 2)\n",
+			wantIs:   ErrVarOutOfRange,
+			wantKind: KindVarOutOfRange,
+			wantLine: 2,
+		},
+		{
+			desc:     "drat unterminated line",
+			read:     readDRAT,
+			input:    "1 2 0\nd 1 2\n",
+			wantIs:   ErrUnterminatedClause,
+			wantKind: KindUnterminatedClause,
+			wantLine: 2,
+		},
+		{
+			desc:     "lrat zero in the middle of antecedents",
+			read:     readLRAT,
+			input:    "5 1 0 2 0 3\n",
+			wantIs:   ErrZeroLiteral,
+			wantKind: KindZeroMidClause,
+			wantLine: 1,
+		},
+		{
+			desc:     "solution missing status line",
+			read:     readSolution,
+			input:    "c comment\nv 1 0\n",
+			wantIs:   ErrNoProblemLine,
+			wantKind: KindNoProblem,
+			wantLine: 2,
+		},
+		{
+			desc:     "solution duplicate status line",
+			read:     readSolution,
+			input:    "s UNSATISFIABLE\ns UNSATISFIABLE\n",
+			wantIs:   ErrDuplicateProblem,
+			wantKind: KindDuplicateProblem,
+			wantLine: 2,
+		},
+		{
+			desc:     "solution unterminated value lines",
+			read:     readSolution,
+			input:    "s SATISFIABLE\nv 1 -2\n",
+			wantIs:   ErrUnterminatedClause,
+			wantKind: KindUnterminatedClause,
+			wantLine: 2,
+		},
+		{
+			desc:     "opb invalid header",
+			read:     readOPB,
+			input:    "* #variable= x #constraint= 1\n+1 x1 >= 1 ;\n",
+			wantIs:   ErrInvalidProblemLine,
+			wantKind: KindBadProblem,
+			wantLine: 1,
+		},
+		{
+			desc:     "opb missing constraints",
+			read:     readOPB,
+			input:    "* #variable= 1 #constraint= 2\n+1 x1 >= 1 ;\n",
+			wantIs:   ErrMissingClauses,
+			wantKind: KindMissingClauses,
+			wantLine: 2,
+		},
+		{
+			desc:     "opb unterminated statement",
+			read:     readOPB,
+			input:    "+1 x1 >= 1\n",
+			wantKind: KindOther,
+			wantLine: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.read(tc.input)
+
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("want *ParseError, got %v", err)
+			}
+			if tc.wantIs != nil && !errors.Is(err, tc.wantIs) {
+				t.Errorf("want error %q, got %v", tc.wantIs, err)
+			}
+			if pe.Kind != tc.wantKind {
+				t.Errorf("want kind %v, got %v", tc.wantKind, pe.Kind)
+			}
+			if pe.Line != tc.wantLine {
+				t.Errorf("want line %d, got %d", tc.wantLine, pe.Line)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package dimacs
+
+import "fmt"
+
+// Satisfies returns true if the given assignment satisfies every clause of the
+// formula, and false as soon as an unsatisfied clause is found. Variable v is
+// assigned to true if assignment[v-1] is positive and to false if it is
+// negative, which is the convention used by SAT solvers to report models (e.g.
+// [1 -2 3] assigns variables 1 and 3 to true and variable 2 to false).
+//
+// An error is returned if the length of the assignment differs from NumVars,
+// if an entry of the assignment is zero, or if a clause references a variable
+// that is not in range 1..NumVars.
+func (f CNFFormula) Satisfies(assignment []int) (bool, error) {
+	if len(assignment) != f.NumVars {
+		return false, fmt.Errorf("assignment has %d values, expected %d", len(assignment), f.NumVars)
+	}
+	for i, a := range assignment {
+		if a == 0 {
+			return false, fmt.Errorf("variable %d has no value in assignment", i+1)
+		}
+	}
+	for _, c := range f.Clauses {
+		satisfied := false
+		for _, l := range c {
+			v := abs(l)
+			if v == 0 || v > f.NumVars {
+				return false, fmt.Errorf("literal %d out of range 1..%d", l, f.NumVars)
+			}
+			if (l > 0) == (assignment[v-1] > 0) {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,105 @@
+package dimacs
+
+import "testing"
+
+func TestSatisfies(t *testing.T) {
+	f := CNFFormula{
+		NumVars: 3,
+		Clauses: [][]int{{1, 2, 3}, {1, -2, 3}, {1, -3}, {-2, -3}},
+	}
+
+	testCases := []struct {
+		desc       string
+		formula    CNFFormula
+		assignment []int
+		want       bool
+		wantErr    bool
+	}{
+		{
+			desc:       "satisfying assignment",
+			formula:    f,
+			assignment: []int{1, -2, 3},
+			want:       true,
+			wantErr:    false,
+		},
+		{
+			desc:       "satisfying assignment (any magnitude)",
+			formula:    f,
+			assignment: []int{5, -1, 7},
+			want:       true,
+			wantErr:    false,
+		},
+		{
+			desc:       "unsatisfied clause",
+			formula:    f,
+			assignment: []int{-1, -2, 3},
+			want:       false,
+			wantErr:    false,
+		},
+		{
+			desc:       "empty clause",
+			formula:    CNFFormula{NumVars: 1, Clauses: [][]int{{1}, {}}},
+			assignment: []int{1},
+			want:       false,
+			wantErr:    false,
+		},
+		{
+			desc:       "no clauses",
+			formula:    CNFFormula{NumVars: 1},
+			assignment: []int{-1},
+			want:       true,
+			wantErr:    false,
+		},
+		{
+			desc:       "assignment too short",
+			formula:    f,
+			assignment: []int{1, -2},
+			want:       false,
+			wantErr:    true,
+		},
+		{
+			desc:       "assignment too long",
+			formula:    f,
+			assignment: []int{1, -2, 3, 4},
+			want:       false,
+			wantErr:    true,
+		},
+		{
+			desc:       "zero value",
+			formula:    f,
+			assignment: []int{1, 0, 3},
+			want:       false,
+			wantErr:    true,
+		},
+		{
+			desc:       "zero literal",
+			formula:    CNFFormula{NumVars: 2, Clauses: [][]int{{0, 1}}},
+			assignment: []int{1, 2},
+			want:       false,
+			wantErr:    true,
+		},
+		{
+			desc:       "variable out of range",
+			formula:    CNFFormula{NumVars: 1, Clauses: [][]int{{-1, 2}}},
+			assignment: []int{1},
+			want:       false,
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotErr := tc.formula.Satisfies(tc.assignment)
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("Satisfies(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("Satisfies(): want no error, got %s", gotErr)
+			}
+			if got != tc.want {
+				t.Errorf("Satisfies(): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
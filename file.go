@@ -0,0 +1,99 @@
+package dimacs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// ReadCNFFile parses and returns a DIMACS CNF formula from the named file.
+// Compressed files are detected from their content (not their extension) and
+// transparently decompressed. Supported compression formats are:
+//   - gzip
+//   - bzip2
+//
+// Files compressed with xz are detected but not supported, in which case an
+// error is returned. Files that do not start with a known magic number are
+// read as plain text.
+func ReadCNFFile(name string) (CNFFormula, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return CNFFormula{}, err
+	}
+	defer f.Close()
+
+	r, err := decompress(f)
+	if err != nil {
+		return CNFFormula{}, err
+	}
+	return ReadCNF(r)
+}
+
+// ReadCNFFS is like ReadCNFFile but opens the named file from fsys (e.g. an
+// embed.FS).
+func ReadCNFFS(fsys fs.FS, name string) (CNFFormula, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return CNFFormula{}, err
+	}
+	defer f.Close()
+
+	r, err := decompress(f)
+	if err != nil {
+		return CNFFormula{}, err
+	}
+	return ReadCNF(r)
+}
+
+// ReadBuilderFS reads the named DIMACS file from fsys and populates the given
+// builder as ReadBuilder does. Compressed files are handled as in ReadCNFFile.
+func ReadBuilderFS(fsys fs.FS, name string, b Builder) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r, err := decompress(f)
+	if err != nil {
+		return err
+	}
+	return ReadBuilder(r, b)
+}
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	xzMagic    = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+)
+
+// isCompressed returns true if data starts with the magic number of a
+// compression format recognized by decompress.
+func isCompressed(data []byte) bool {
+	return bytes.HasPrefix(data, gzipMagic) || bytes.HasPrefix(data, bzip2Magic) || bytes.HasPrefix(data, xzMagic)
+}
+
+// decompress returns a reader that yields the decompressed content of r if r
+// starts with the magic number of a supported compression format, and the
+// content of r unchanged otherwise.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(xzMagic)) // longest magic number
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(magic, bzip2Magic):
+		return bzip2.NewReader(br), nil
+	case bytes.HasPrefix(magic, xzMagic):
+		return nil, errors.New("xz compression is not supported")
+	}
+	return br, nil
+}
@@ -0,0 +1,181 @@
+package dimacs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatalf("gzip: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("gzip: %s", err)
+	}
+	return buf.Bytes()
+}
+
+// testFormulaBzip2 is testFormulaDIMACS compressed with bzip2 (the standard
+// library does not provide a bzip2 writer).
+var testFormulaBzip2 = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x4b, 0x0a,
+	0xed, 0x2b, 0x00, 0x00, 0x14, 0xd9, 0x80, 0x00, 0x10, 0x40, 0x02, 0x7c,
+	0x00, 0x09, 0x01, 0x40, 0x00, 0x20, 0x00, 0x21, 0x88, 0x87, 0xa9, 0xa7,
+	0xa8, 0x40, 0xd0, 0x34, 0x2c, 0xb5, 0xb1, 0xc6, 0x05, 0x50, 0xcb, 0x8f,
+	0xa4, 0xa3, 0x94, 0x82, 0x09, 0x19, 0x35, 0xf1, 0x77, 0x24, 0x53, 0x85,
+	0x09, 0x04, 0xb0, 0xae, 0xd2, 0xb0,
+}
+
+func TestReadCNFFile(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.cnf")
+	compressed := filepath.Join(dir, "compressed.cnf.gz")
+	if err := os.WriteFile(plain, []byte(testFormulaDIMACS), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(compressed, gzipped(t, testFormulaDIMACS), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	bz2 := filepath.Join(dir, "compressed.cnf.bz2")
+	if err := os.WriteFile(bz2, testFormulaBzip2, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	xz := filepath.Join(dir, "compressed.cnf.xz")
+	if err := os.WriteFile(xz, append(xzMagic, "content"...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc    string
+		name    string
+		wantCNF CNFFormula
+		wantErr bool
+	}{
+		{
+			desc:    "plain file",
+			name:    plain,
+			wantCNF: testFormula,
+			wantErr: false,
+		},
+		{
+			desc:    "gzipped file",
+			name:    compressed,
+			wantCNF: testFormula,
+			wantErr: false,
+		},
+		{
+			desc:    "bzip2 file",
+			name:    bz2,
+			wantCNF: testFormula,
+			wantErr: false,
+		},
+		{
+			desc:    "xz file",
+			name:    xz,
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:    "missing file",
+			name:    filepath.Join(dir, "missing.cnf"),
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotCNF, gotErr := ReadCNFFile(tc.name)
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadCNFFile(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadCNFFile(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantCNF, gotCNF); diff != "" {
+				t.Errorf("ReadCNFFile(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadCNFFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"plain.cnf":      {Data: []byte(testFormulaDIMACS)},
+		"compressed.cnf": {Data: gzipped(t, testFormulaDIMACS)},
+		"empty.cnf":      {Data: []byte{}},
+	}
+
+	testCases := []struct {
+		desc    string
+		name    string
+		wantCNF CNFFormula
+		wantErr bool
+	}{
+		{
+			desc:    "plain file",
+			name:    "plain.cnf",
+			wantCNF: testFormula,
+			wantErr: false,
+		},
+		{
+			desc:    "gzipped file",
+			name:    "compressed.cnf",
+			wantCNF: testFormula,
+			wantErr: false,
+		},
+		{
+			desc:    "empty file",
+			name:    "empty.cnf",
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:    "missing file",
+			name:    "missing.cnf",
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotCNF, gotErr := ReadCNFFS(fsys, tc.name)
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadCNFFS(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadCNFFS(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantCNF, gotCNF); diff != "" {
+				t.Errorf("ReadCNFFS(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadBuilderFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"compressed.cnf": {Data: gzipped(t, validCNF_manyComments)},
+	}
+	cr := &commentRecorder{}
+
+	if err := ReadBuilderFS(fsys, "compressed.cnf", cr); err != nil {
+		t.Fatalf("ReadBuilderFS(): want no error, got %s", err)
+	}
+
+	if got := len(cr.comments); got != 5 {
+		t.Errorf("ReadBuilderFS(): want 5 comments, got %d", got)
+	}
+}
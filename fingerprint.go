@@ -0,0 +1,71 @@
+package dimacs
+
+import (
+	"crypto/sha256"
+	"io"
+	"sort"
+)
+
+// Fingerprint returns a SHA-256 hash of the canonical form of the formula, so
+// that formulas that only differ by the order of their clauses or of the
+// literals within their clauses have the same fingerprint. The canonical form
+// is obtained as follows:
+//
+//   - the literals of each clause are sorted by variable and then by sign,
+//     the negative literal coming first (e.g. [-1 1 -2 3]);
+//   - the clauses are sorted in lexicographic order of their sorted literals,
+//     a clause coming before the clauses it is a prefix of.
+//
+// The hash is then computed over the formula written in the DIMACS CNF format
+// by WriteCNF, that is without comments and with normalized whitespace. NumVars
+// and the number of clauses are part of the hash. Repeated literals and
+// duplicate clauses are kept and thus change the fingerprint; use Normalize
+// beforehand to ignore them. The formula is not modified.
+func (f CNFFormula) Fingerprint() [32]byte {
+	c := f.Clone()
+	for _, clause := range c.Clauses {
+		sortLiterals(clause)
+	}
+	sort.Slice(c.Clauses, func(i, j int) bool {
+		return lessClause(c.Clauses[i], c.Clauses[j])
+	})
+
+	h := sha256.New()
+	writeCNF(h, c, -1) // writing to a hash never fails
+	var sum [32]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// Fingerprint reads a DIMACS CNF file from r and returns the fingerprint of
+// its formula (see CNFFormula.Fingerprint). The file is validated as in
+// ReadCNF. The whole formula is kept in memory as the clauses must be sorted
+// before being hashed.
+func Fingerprint(r io.Reader) ([32]byte, error) {
+	f, err := ReadCNF(r)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return f.Fingerprint(), nil
+}
+
+// lessClause reports whether clause a comes before clause b in the order of
+// their sorted literals (see sortLiterals).
+func lessClause(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return lessLiteral(a[i], b[i])
+		}
+	}
+	return len(a) < len(b)
+}
+
+// lessLiteral reports whether literal a comes before literal b when ordered by
+// variable and then by sign.
+func lessLiteral(a, b int) bool {
+	va, vb := abs(a), abs(b)
+	if va != vb {
+		return va < vb
+	}
+	return a < b
+}
@@ -0,0 +1,103 @@
+package dimacs
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFingerprint(t *testing.T) {
+	base := CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {3, -1, 2}, {-3}, {}}}
+	testCases := []struct {
+		desc      string
+		formula   CNFFormula
+		wantEqual bool
+	}{
+		{
+			desc:      "same formula",
+			formula:   CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {3, -1, 2}, {-3}, {}}},
+			wantEqual: true,
+		},
+		{
+			desc:      "reordered clauses and literals",
+			formula:   CNFFormula{NumVars: 3, Clauses: [][]int{{}, {-3}, {2, 3, -1}, {-2, 1}}},
+			wantEqual: true,
+		},
+		{
+			desc:      "different number of variables",
+			formula:   CNFFormula{NumVars: 4, Clauses: [][]int{{1, -2}, {3, -1, 2}, {-3}, {}}},
+			wantEqual: false,
+		},
+		{
+			desc:      "different literal",
+			formula:   CNFFormula{NumVars: 3, Clauses: [][]int{{1, 2}, {3, -1, 2}, {-3}, {}}},
+			wantEqual: false,
+		},
+		{
+			desc:      "duplicate clause",
+			formula:   CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {3, -1, 2}, {-3}, {}, {-3}}},
+			wantEqual: false,
+		},
+		{
+			desc:      "repeated literal",
+			formula:   CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2, 1}, {3, -1, 2}, {-3}, {}}},
+			wantEqual: false,
+		},
+	}
+
+	want := base.Fingerprint()
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tc.formula.Fingerprint()
+
+			if (got == want) != tc.wantEqual {
+				t.Errorf("Fingerprint(): want equal fingerprints %t, got %x and %x", tc.wantEqual, want, got)
+			}
+		})
+	}
+}
+
+func TestFingerprint_canonicalForm(t *testing.T) {
+	f := CNFFormula{NumVars: 3, Clauses: [][]int{{3, -1}, {2, 1, -2}, {1}, {-1, 2}}}
+	canonical := "p cnf 3 4\n-1 2 0\n-1 3 0\n1 0\n1 -2 2 0\n"
+
+	got := f.Fingerprint()
+
+	if want := sha256.Sum256([]byte(canonical)); got != want {
+		t.Errorf("Fingerprint(): want %x, got %x", want, got)
+	}
+}
+
+func TestFingerprint_unmodified(t *testing.T) {
+	f := CNFFormula{NumVars: 2, Clauses: [][]int{{2, -1}, {1}}}
+	want := f.Clone()
+
+	f.Fingerprint()
+
+	if diff := cmp.Diff(want, f); diff != "" {
+		t.Errorf("Fingerprint(): formula modified (-want +got):\n%s", diff)
+	}
+}
+
+func TestFingerprint_reader(t *testing.T) {
+	a := "c first file\np cnf 3 2\n1 -2 0\n3 0\n"
+	b := "p  cnf 3 2\n\n  3   0\nc comment\n-2 1 0\n"
+
+	fa, err := Fingerprint(strings.NewReader(a))
+	if err != nil {
+		t.Fatalf("Fingerprint(): want no error, got %s", err)
+	}
+	fb, err := Fingerprint(strings.NewReader(b))
+	if err != nil {
+		t.Fatalf("Fingerprint(): want no error, got %s", err)
+	}
+
+	if fa != fb {
+		t.Errorf("Fingerprint(): want equal fingerprints, got %x and %x", fa, fb)
+	}
+	if _, err := Fingerprint(strings.NewReader("p cnf 3 2\n1 -2 0\n")); err == nil {
+		t.Errorf("Fingerprint(): want error, got nil")
+	}
+}
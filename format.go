@@ -0,0 +1,138 @@
+package dimacs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is a DIMACS-based file format.
+type Format int
+
+const (
+	// FormatUnknown is returned by DetectFormat for files whose format could
+	// not be determined.
+	FormatUnknown Format = iota
+	FormatCNF            // "p cnf"
+	FormatWCNF           // "p wcnf" (weighted CNF)
+	FormatQCNF           // "p cnf" followed by quantifier lines (QDIMACS)
+	FormatICNF           // "p inccnf" (incremental CNF)
+	FormatGCNF           // "p gcnf" (group-oriented CNF)
+	FormatSAT            // "p sat" and its variants
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatCNF:
+		return "cnf"
+	case FormatWCNF:
+		return "wcnf"
+	case FormatQCNF:
+		return "qcnf"
+	case FormatICNF:
+		return "inccnf"
+	case FormatGCNF:
+		return "gcnf"
+	case FormatSAT:
+		return "sat"
+	default:
+		return "unknown"
+	}
+}
+
+// DetectFormat reads r up to its problem line to determine the format of the
+// file. QDIMACS files, whose problem line is "p cnf", are recognized by the
+// quantifier line ("a ..." or "e ...") following it. FormatUnknown is
+// returned if the problem line is missing, is preceded by a line that is not
+// a comment, or declares an unknown problem type.
+//
+// The returned reader yields the whole content of r, including the lines read
+// to detect the format, and should be used in place of r.
+func DetectFormat(r io.Reader) (Format, io.Reader, error) {
+	br := bufio.NewReader(r)
+	consumed := &bytes.Buffer{}
+	format, err := detectFormat(br, consumed)
+	if err != nil {
+		return FormatUnknown, nil, err
+	}
+	return format, io.MultiReader(consumed, br), nil
+}
+
+// detectFormat implements DetectFormat, copying the lines it reads from br to
+// consumed.
+func detectFormat(br *bufio.Reader, consumed *bytes.Buffer) (Format, error) {
+	format := FormatUnknown
+	for n := 0; ; n++ {
+		line, err := br.ReadBytes('\n')
+		consumed.Write(line)
+		if err == io.EOF && len(line) == 0 {
+			return format, nil
+		}
+		if err != nil && err != io.EOF {
+			return FormatUnknown, err
+		}
+		if n == 0 {
+			line = bytes.TrimPrefix(line, []byte(utf8BOM))
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == 'c' {
+			continue
+		}
+
+		if format == FormatCNF { // first line after "p cnf"
+			if len(line) > 1 && (line[0] == 'a' || line[0] == 'e') && isSpace(line[1]) {
+				return FormatQCNF, nil
+			}
+			return FormatCNF, nil
+		}
+		if line[0] != 'p' {
+			return FormatUnknown, nil
+		}
+		switch fields := strings.Fields(string(line)); {
+		case len(fields) < 2:
+			return FormatUnknown, nil
+		case fields[1] == "cnf":
+			format = FormatCNF // look for quantifiers on the next line
+		case fields[1] == "wcnf":
+			return FormatWCNF, nil
+		case fields[1] == "inccnf":
+			return FormatICNF, nil
+		case fields[1] == "gcnf":
+			return FormatGCNF, nil
+		case strings.HasPrefix(fields[1], "sat"):
+			return FormatSAT, nil
+		default:
+			return FormatUnknown, nil
+		}
+	}
+}
+
+// Read detects the format of the file read from r (see DetectFormat) and
+// parses it with the corresponding function of this package. It returns a
+// CNFFormula for CNF files (see ReadCNF), an ICNFFormula for incremental CNF
+// files (see ReadICNF), a GCNFFormula for group-oriented CNF files (see
+// ReadGCNF) and a SATFormula for SAT files (see ReadSAT). Weighted CNF and
+// QDIMACS files are not supported by Read, in which case an error is returned.
+func Read(r io.Reader) (any, error) {
+	format, r, err := DetectFormat(r)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatCNF:
+		return ReadCNF(r)
+	case FormatICNF:
+		f, assumptions, err := ReadICNF(r)
+		return ICNFFormula{Formula: f, Assumptions: assumptions}, err
+	case FormatGCNF:
+		return ReadGCNF(r)
+	case FormatSAT:
+		return ReadSAT(r)
+	case FormatUnknown:
+		return nil, fmt.Errorf("unknown format")
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
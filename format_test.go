@@ -0,0 +1,143 @@
+package dimacs
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetectFormat(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		input string
+		want  Format
+	}{
+		{desc: "cnf", input: validCNF_manyComments, want: FormatCNF},
+		{desc: "cnf without clauses", input: "p cnf 0 0", want: FormatCNF},
+		{desc: "cnf with byte order mark", input: "\ufeffp cnf 1 1\n1 0\n", want: FormatCNF},
+		{desc: "qcnf", input: "c qdimacs\np cnf 3 1\n\nc quantifiers\na 1 2 0\ne 3 0\n1 -3 0\n", want: FormatQCNF},
+		{desc: "qcnf existential first", input: "p cnf 3 1\ne 3 0\n1 -3 0\n", want: FormatQCNF},
+		{desc: "wcnf", input: "p wcnf 2 1 10\n10 1 2 0\n", want: FormatWCNF},
+		{desc: "icnf", input: "p inccnf\n1 2 0\na -1 0\n", want: FormatICNF},
+		{desc: "gcnf", input: validGCNF, want: FormatGCNF},
+		{desc: "sat", input: validSAT, want: FormatSAT},
+		{desc: "satx", input: "p satx 2\nxor(1 2)\n", want: FormatSAT},
+		{desc: "empty input", input: "", want: FormatUnknown},
+		{desc: "no problem line", input: "c comment\n1 2 0\n", want: FormatUnknown},
+		{desc: "unknown problem", input: "p edge 3 2\ne 1 2\n", want: FormatUnknown},
+		{desc: "invalid problem line", input: "p\n", want: FormatUnknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, r, err := DetectFormat(iotest.OneByteReader(strings.NewReader(tc.input)))
+
+			if err != nil {
+				t.Fatalf("DetectFormat(): want no error, got %s", err)
+			}
+			if got != tc.want {
+				t.Errorf("DetectFormat(): want format %s, got %s", tc.want, got)
+			}
+			content, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("ReadAll(): want no error, got %s", err)
+			}
+			if diff := cmp.Diff(tc.input, string(content)); diff != "" {
+				t.Errorf("DetectFormat(): content mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDetectFormat_readError(t *testing.T) {
+	wantErr := errors.New("read error")
+	r := io.MultiReader(strings.NewReader("c comment\n"), iotest.ErrReader(wantErr))
+
+	_, _, err := DetectFormat(r)
+
+	if !errors.Is(err, wantErr) {
+		t.Errorf("DetectFormat(): want error %q, got %v", wantErr, err)
+	}
+}
+
+func TestRead_dispatch(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		want    any
+		wantErr bool
+	}{
+		{
+			desc:  "cnf",
+			input: validCNF_manyComments,
+			want:  testFormula,
+		},
+		{
+			desc:  "gcnf",
+			input: validGCNF,
+			want: GCNFFormula{
+				NumVars:   3,
+				NumGroups: 2,
+				Clauses:   [][]int{{1, 2}, {-1}, {-2, 3}, {-3}},
+				Groups:    []int{0, 1, 2, 2},
+			},
+		},
+		{
+			desc:  "icnf",
+			input: validICNF,
+			want: ICNFFormula{
+				Formula:     CNFFormula{NumVars: 3, Clauses: [][]int{{1, 2}, {-2, 3}}},
+				Assumptions: [][]int{{-1}, {-3}, {}},
+			},
+		},
+		{
+			desc:  "sat",
+			input: "p sat 2\n+(1 -2)\n",
+			want:  SATFormula{NumVars: 2, Expr: Or{Lit(1), Lit(-2)}},
+		},
+		{
+			desc:    "invalid cnf",
+			input:   "p cnf 2 1\n1 x 0\n",
+			want:    CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:    "invalid icnf",
+			input:   "p inccnf\n1 2 0\na 3 0\n",
+			want:    ICNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:    "unsupported format",
+			input:   "p wcnf 2 1 10\n10 1 2 0\n",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			desc:    "unknown format",
+			input:   "1 2 0\n",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotErr := Read(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("Read(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("Read(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Read(): result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package dimacs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// GCNFFormula represents a group-oriented CNF formula as used for group-MUS
+// (Minimal Unsatisfiable Subset) extraction. Groups[i] is the group of clause
+// Clauses[i]. Groups are denoted by integers from 0 to NumGroups (inclusive),
+// group 0 being the "hard" background group.
+type GCNFFormula struct {
+	NumVars   int
+	NumGroups int
+	Clauses   [][]int
+	Groups    []int
+}
+
+// ReadGCNF parses and returns a group-oriented CNF formula from the given
+// reader. The problem line is "p gcnf <vars> <clauses> <groups>" and every
+// clause line is prefixed by its group in braces (e.g. "{2} 1 -3 0"). Comment
+// lines (starting with "c") are ignored. Group indices must be in
+// [0, NumGroups] and the number of clauses must match the one declared in the
+// problem line. As with ReadCNF, lines can be arbitrarily long.
+func ReadGCNF(r io.Reader) (GCNFFormula, error) {
+	rd := Reader{}
+	rd.Reset(r)
+	var f *GCNFFormula
+	nClauses := 0
+	var buf []int
+
+	n := 0
+	for {
+		b, err := rd.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return GCNFFormula{}, err
+		}
+		n++
+		if n == 1 {
+			b = bytes.TrimPrefix(b, []byte(utf8BOM))
+		}
+		line := string(bytes.TrimSpace(b))
+		if line == "" {
+			continue
+		}
+
+		switch line[0] {
+		case 'c': // comment
+			continue
+		case 'p': // problem
+			if f != nil {
+				return GCNFFormula{}, newParseError(n, line, ErrDuplicateProblem)
+			}
+			var err error
+			f, nClauses, err = parseGCNFProblem(line)
+			if err != nil {
+				return GCNFFormula{}, newParseError(n, line, err)
+			}
+		default: // clause
+			if f == nil {
+				return GCNFFormula{}, newParseError(n, line, ErrClauseBeforeProblem)
+			}
+			if s := len(f.Clauses); s == nClauses {
+				err := &ClauseCountError{Declared: s, Actual: s + 1, TooMany: true}
+				return GCNFFormula{}, newParseError(n, line, err)
+			}
+			group, rest, err := parseGroup(line, f.NumGroups)
+			if err != nil {
+				return GCNFFormula{}, newParseError(n, line, err)
+			}
+			buf, _, err = parseClause(rest, buf[:0])
+			if err != nil {
+				return GCNFFormula{}, newParseError(n, line, err)
+			}
+			f.Clauses = append(f.Clauses, append([]int{}, buf...))
+			f.Groups = append(f.Groups, group)
+		}
+	}
+
+	if f == nil {
+		return GCNFFormula{}, newParseError(n, "", ErrNoProblemLine)
+	}
+	if got := len(f.Clauses); got < nClauses {
+		return GCNFFormula{}, newParseError(n, "", &ClauseCountError{Declared: nClauses, Actual: got})
+	}
+	return *f, nil
+}
+
+// parseGCNFProblem parses a "p gcnf <vars> <clauses> <groups>" problem line and
+// returns an empty formula along with the declared number of clauses.
+func parseGCNFProblem(line string) (*GCNFFormula, int, error) {
+	parts := strings.Fields(line)
+	if len(parts) != 5 {
+		return nil, 0, fmt.Errorf("%w: should have 5 parts, got %d: %s", ErrInvalidProblemLine, len(parts), line)
+	}
+	if parts[1] != "gcnf" {
+		return nil, 0, fmt.Errorf("%w: expected \"gcnf\" problem, got %q", ErrInvalidProblemType, parts[1])
+	}
+	var counts [3]int
+	for i, name := range []string{"variables", "clauses", "groups"} {
+		c, err := strconv.Atoi(parts[i+2])
+		if err != nil || c < 0 {
+			return nil, 0, fmt.Errorf("%w: invalid number of %s %q", ErrInvalidProblemLine, name, parts[i+2])
+		}
+		counts[i] = c
+	}
+	f := &GCNFFormula{
+		NumVars:   counts[0],
+		NumGroups: counts[2],
+		Clauses:   make([][]int, 0, counts[1]),
+		Groups:    make([]int, 0, counts[1]),
+	}
+	return f, counts[1], nil
+}
+
+// parseGroup parses the "{g}" group prefix of a clause line and returns the
+// group along with the remainder of the line.
+func parseGroup(line string, numGroups int) (int, string, error) {
+	end := strings.IndexByte(line, '}')
+	if line[0] != '{' || end < 0 {
+		return 0, "", fmt.Errorf("clause should start with a group in braces: %q", line)
+	}
+	g, err := strconv.Atoi(strings.TrimSpace(line[1:end]))
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid group in clause %q: %w", line, err)
+	}
+	if g < 0 || g > numGroups {
+		return 0, "", fmt.Errorf("group %d out of range [0, %d]", g, numGroups)
+	}
+	return g, line[end+1:], nil
+}
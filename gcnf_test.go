@@ -0,0 +1,146 @@
+package dimacs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const validGCNF = `c group-oriented CNF
+p gcnf 3 4 2
+{0} 1 2 0
+{1} -1 0
+{2} -2 3 0
+{2}-3 0
+`
+
+func TestReadGCNF(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		input    string
+		wantGCNF GCNFFormula
+		wantErr  bool
+	}{
+		{
+			desc:     "byte order mark",
+			input:    "\ufeffp gcnf 2 1 1\n{1} 1 -2 0\n",
+			wantGCNF: GCNFFormula{NumVars: 2, NumGroups: 1, Clauses: [][]int{{1, -2}}, Groups: []int{1}},
+			wantErr:  false,
+		},
+		{
+			desc:  "valid gcnf",
+			input: validGCNF,
+			wantGCNF: GCNFFormula{
+				NumVars:   3,
+				NumGroups: 2,
+				Clauses:   [][]int{{1, 2}, {-1}, {-2, 3}, {-3}},
+				Groups:    []int{0, 1, 2, 2},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "missing problem line",
+			input:   "c comment\n",
+			wantErr: true,
+		},
+		{
+			desc:    "cnf problem line",
+			input:   "p cnf 3 1 1\n{0} 1 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "missing group count",
+			input:   "p gcnf 3 1\n{0} 1 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid group count",
+			input:   "p gcnf 3 1 x\n{0} 1 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "duplicate problem line",
+			input:   "p gcnf 3 1 1\np gcnf 3 1 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "clause before problem line",
+			input:   "{0} 1 0\np gcnf 3 1 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "missing group",
+			input:   "p gcnf 3 1 1\n1 2 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid group",
+			input:   "p gcnf 3 1 1\n{a} 1 2 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "group out of range",
+			input:   "p gcnf 3 1 1\n{2} 1 2 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "negative group",
+			input:   "p gcnf 3 1 1\n{-1} 1 2 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid literal",
+			input:   "p gcnf 3 1 1\n{1} 1 x 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "too many clauses",
+			input:   "p gcnf 3 1 1\n{1} 1 0\n{1} 2 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "missing clauses",
+			input:   "p gcnf 3 2 1\n{1} 1 0\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotGCNF, gotErr := ReadGCNF(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadGCNF(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadGCNF(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantGCNF, gotGCNF); diff != "" {
+				t.Errorf("ReadGCNF(): formula mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadGCNF_longLines(t *testing.T) {
+	nVars := 100000
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "p gcnf %d 1 1\n{1}", nVars)
+	want := GCNFFormula{NumVars: nVars, NumGroups: 1, Clauses: [][]int{make([]int, nVars)}, Groups: []int{1}}
+	for i := 1; i <= nVars; i++ {
+		fmt.Fprintf(&sb, " %d", i)
+		want.Clauses[0][i-1] = i
+	}
+	sb.WriteString(" 0\n")
+
+	got, err := ReadGCNF(strings.NewReader(sb.String()))
+
+	if err != nil {
+		t.Fatalf("ReadGCNF(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ReadGCNF(): GCNF mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,124 @@
+package dimacs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Graph represents an undirected graph in the DIMACS graph format (as used in
+// graph coloring and max-clique benchmarks). Nodes are denoted by integers
+// from 1 to NumNodes (inclusive) and each edge by the pair of its end nodes.
+type Graph struct {
+	NumNodes int
+	Edges    [][2]int
+}
+
+// ReadGraph parses and returns a graph in the DIMACS edge format from the given
+// reader. The problem line is "p edge <nodes> <edges>" ("p col" is accepted as
+// well) and each edge is given by a line "e <u> <v>". Comment lines (starting
+// with "c") are ignored. Node indices must be in [1, NumNodes] and the number
+// of edges must match the one declared in the problem line.
+func ReadGraph(r io.Reader) (Graph, error) {
+	rd := Reader{}
+	rd.Reset(r)
+	var g *Graph
+	nEdges := 0
+
+	n := 0
+	for {
+		b, err := rd.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Graph{}, err
+		}
+		n++
+		if n == 1 {
+			b = bytes.TrimPrefix(b, []byte(utf8BOM))
+		}
+		line := strings.TrimSpace(string(b))
+		if line == "" {
+			continue
+		}
+
+		switch line[0] {
+		case 'c': // comment
+			continue
+		case 'p': // problem
+			if g != nil {
+				return Graph{}, newParseError(n, line, ErrDuplicateProblem)
+			}
+			parts := strings.Fields(line)
+			if len(parts) != 4 {
+				err := fmt.Errorf("%w: should have 4 parts, got %d: %s", ErrInvalidProblemLine, len(parts), line)
+				return Graph{}, newParseError(n, line, err)
+			}
+			if parts[1] != "edge" && parts[1] != "col" {
+				err := fmt.Errorf("%w: expected \"edge\" problem, got %q", ErrInvalidProblemType, parts[1])
+				return Graph{}, newParseError(n, line, err)
+			}
+			nNodes, err := strconv.Atoi(parts[2])
+			if err != nil || nNodes < 0 {
+				err := fmt.Errorf("%w: invalid number of nodes %q", ErrInvalidProblemLine, parts[2])
+				return Graph{}, newParseError(n, line, err)
+			}
+			nEdges, err = strconv.Atoi(parts[3])
+			if err != nil || nEdges < 0 {
+				err := fmt.Errorf("%w: invalid number of edges %q", ErrInvalidProblemLine, parts[3])
+				return Graph{}, newParseError(n, line, err)
+			}
+			g = &Graph{NumNodes: nNodes, Edges: make([][2]int, 0, nEdges)}
+		case 'e': // edge
+			if g == nil {
+				err := fmt.Errorf("%w: %q", ErrClauseBeforeProblem, line)
+				return Graph{}, newParseError(n, line, err)
+			}
+			if len(g.Edges) == nEdges {
+				err := fmt.Errorf("%w: expected %d edges", ErrTooManyClauses, nEdges)
+				return Graph{}, newParseError(n, line, err)
+			}
+			edge, err := parseEdge(line, g.NumNodes)
+			if err != nil {
+				return Graph{}, newParseError(n, line, err)
+			}
+			g.Edges = append(g.Edges, edge)
+		default:
+			err := fmt.Errorf("unexpected line %q", line)
+			return Graph{}, newParseError(n, line, err)
+		}
+	}
+
+	if g == nil {
+		return Graph{}, newParseError(n, "", ErrNoProblemLine)
+	}
+	if got := len(g.Edges); got < nEdges {
+		err := fmt.Errorf("%w: expected %d edges, got %d", ErrMissingClauses, nEdges, got)
+		return Graph{}, newParseError(n, "", err)
+	}
+	return *g, nil
+}
+
+// parseEdge parses an edge line "e <u> <v>" whose nodes must be in
+// [1, numNodes].
+func parseEdge(line string, numNodes int) ([2]int, error) {
+	parts := strings.Fields(line)
+	if len(parts) != 3 || parts[0] != "e" {
+		return [2]int{}, fmt.Errorf("edge line should be of the form \"e <u> <v>\": %q", line)
+	}
+	var edge [2]int
+	for i, p := range parts[1:] {
+		u, err := strconv.Atoi(p)
+		if err != nil {
+			return [2]int{}, fmt.Errorf("invalid node in edge %q: %w", line, err)
+		}
+		if u < 1 || u > numNodes {
+			return [2]int{}, fmt.Errorf("%w: node %d, expected nodes in 1..%d", ErrVarOutOfRange, u, numNodes)
+		}
+		edge[i] = u
+	}
+	return edge, nil
+}
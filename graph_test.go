@@ -0,0 +1,139 @@
+package dimacs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const validGraph = `c a triangle
+c with a pendant node
+p edge 4 4
+e 1 2
+e 2 3
+
+e 1 3
+e 3 4
+`
+
+func TestReadGraph(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		input     string
+		wantGraph Graph
+		wantErr   bool
+	}{
+		{
+			desc:      "byte order mark",
+			input:     "\ufeffp edge 2 1\ne 1 2\n",
+			wantGraph: Graph{NumNodes: 2, Edges: [][2]int{{1, 2}}},
+			wantErr:   false,
+		},
+		{
+			desc:  "valid graph",
+			input: validGraph,
+			wantGraph: Graph{
+				NumNodes: 4,
+				Edges:    [][2]int{{1, 2}, {2, 3}, {1, 3}, {3, 4}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:      "col problem",
+			input:     "p col 2 1\ne 1 2\n",
+			wantGraph: Graph{NumNodes: 2, Edges: [][2]int{{1, 2}}},
+			wantErr:   false,
+		},
+		{
+			desc:    "missing problem line",
+			input:   "c comment\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid problem type",
+			input:   "p cnf 2 1\ne 1 2\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid node count",
+			input:   "p edge x 1\ne 1 2\n",
+			wantErr: true,
+		},
+		{
+			desc:    "duplicate problem line",
+			input:   "p edge 2 1\np edge 2 1\ne 1 2\n",
+			wantErr: true,
+		},
+		{
+			desc:    "edge before problem line",
+			input:   "e 1 2\np edge 2 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "node out of range",
+			input:   "p edge 2 1\ne 1 3\n",
+			wantErr: true,
+		},
+		{
+			desc:    "node zero",
+			input:   "p edge 2 1\ne 0 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid node",
+			input:   "p edge 2 1\ne 1 a\n",
+			wantErr: true,
+		},
+		{
+			desc:    "malformed edge",
+			input:   "p edge 2 1\ne 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "too many edges",
+			input:   "p edge 2 1\ne 1 2\ne 2 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "missing edges",
+			input:   "p edge 2 2\ne 1 2\n",
+			wantErr: true,
+		},
+		{
+			desc:    "unexpected line",
+			input:   "p edge 2 1\nn 1 2\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotGraph, gotErr := ReadGraph(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadGraph(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadGraph(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantGraph, gotGraph); diff != "" {
+				t.Errorf("ReadGraph(): graph mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadGraph_longLine(t *testing.T) {
+	input := "c " + strings.Repeat("x", 100000) + "\np edge 2 1\ne 1 2\n"
+
+	got, err := ReadGraph(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadGraph(): want no error, got %s", err)
+	}
+
+	want := Graph{NumNodes: 2, Edges: [][2]int{{1, 2}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ReadGraph(): graph mismatch (-want +got):\n%s", diff)
+	}
+}
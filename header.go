@@ -0,0 +1,86 @@
+package dimacs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Header holds the information declared in the problem line of a DIMACS file.
+type Header struct {
+	// Problem is the problem type (e.g. "cnf", "wcnf" or "gcnf").
+	Problem string
+
+	// NumVars and NumClauses are the declared numbers of variables and
+	// clauses. They are both -1 for incremental CNF files ("p inccnf"), and
+	// NumClauses is -1 for SAT files ("p sat" and "p satx"), which only
+	// declare a number of variables.
+	NumVars    int
+	NumClauses int
+}
+
+// ReadHeader reads the given reader up to the problem line and returns the
+// information it declares, without reading any clause. Comment lines and
+// empty lines before the problem line are skipped. The problem type is not
+// validated and any field following the number of clauses (e.g. the top
+// weight of "p wcnf" files) is ignored.
+//
+// Note that r is read through a buffer and might thus be consumed beyond the
+// problem line.
+func ReadHeader(r io.Reader) (Header, error) {
+	rd := Reader{}
+	rd.Reset(r)
+	for n := 1; ; n++ {
+		line, err := rd.readLine()
+		if err == io.EOF {
+			return Header{}, newParseError(n-1, "", ErrNoProblemLine)
+		}
+		if err != nil {
+			return Header{}, err
+		}
+		if n == 1 {
+			line = bytes.TrimPrefix(line, []byte(utf8BOM))
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == 'c' {
+			continue
+		}
+		if line[0] != 'p' {
+			return Header{}, newParseError(n, string(line), ErrClauseBeforeProblem)
+		}
+		h, err := parseHeader(string(line))
+		if err != nil {
+			return Header{}, newParseError(n, string(line), err)
+		}
+		return h, nil
+	}
+}
+
+// parseHeader parses a problem line of any type.
+func parseHeader(line string) (Header, error) {
+	parts := strings.Fields(line)
+	if len(parts) == 2 && parts[1] == "inccnf" {
+		return Header{Problem: parts[1], NumVars: -1, NumClauses: -1}, nil
+	}
+	if len(parts) == 3 && (parts[1] == "sat" || parts[1] == "satx") {
+		nVars, err := strconv.Atoi(parts[2])
+		if err != nil || nVars < 0 {
+			return Header{}, fmt.Errorf("%w: invalid number of variables %q", ErrInvalidProblemLine, parts[2])
+		}
+		return Header{Problem: parts[1], NumVars: nVars, NumClauses: -1}, nil
+	}
+	if len(parts) < 4 || parts[0] != "p" {
+		return Header{}, fmt.Errorf("%w: should have at least 4 parts, got %d: %s", ErrInvalidProblemLine, len(parts), line)
+	}
+	nVars, err := strconv.Atoi(parts[2])
+	if err != nil || nVars < 0 {
+		return Header{}, fmt.Errorf("%w: invalid number of variables %q", ErrInvalidProblemLine, parts[2])
+	}
+	nClauses, err := strconv.Atoi(parts[3])
+	if err != nil || nClauses < 0 {
+		return Header{}, fmt.Errorf("%w: invalid number of clauses %q", ErrInvalidProblemLine, parts[3])
+	}
+	return Header{Problem: parts[1], NumVars: nVars, NumClauses: nClauses}, nil
+}
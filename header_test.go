@@ -0,0 +1,112 @@
+package dimacs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadHeader(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		input    string
+		want     Header
+		wantLine int
+		wantErr  error
+	}{
+		{
+			desc:  "cnf",
+			input: validCNF_manyComments,
+			want:  Header{Problem: "cnf", NumVars: 3, NumClauses: 4},
+		},
+		{
+			desc:  "clauses are not read",
+			input: "c comment\n\np cnf 2 1\n1 x 0\n1 2 0\n",
+			want:  Header{Problem: "cnf", NumVars: 2, NumClauses: 1},
+		},
+		{
+			desc:  "byte order mark",
+			input: "\ufeffp cnf 2 1\n",
+			want:  Header{Problem: "cnf", NumVars: 2, NumClauses: 1},
+		},
+		{
+			desc:  "wcnf",
+			input: "p wcnf 3 2 10\n10 1 0\n",
+			want:  Header{Problem: "wcnf", NumVars: 3, NumClauses: 2},
+		},
+		{
+			desc:  "inccnf",
+			input: "p inccnf\n1 2 0\n",
+			want:  Header{Problem: "inccnf", NumVars: -1, NumClauses: -1},
+		},
+		{
+			desc:  "sat",
+			input: "p sat 3\n(1 -2)\n",
+			want:  Header{Problem: "sat", NumVars: 3, NumClauses: -1},
+		},
+		{
+			desc:  "satx",
+			input: "p satx 2\nxor(1 2)\n",
+			want:  Header{Problem: "satx", NumVars: 2, NumClauses: -1},
+		},
+		{
+			desc:     "sat with invalid count",
+			input:    "p sat x\n",
+			wantLine: 1,
+			wantErr:  ErrInvalidProblemLine,
+		},
+		{
+			desc:     "no problem line",
+			input:    "c comment\n\n",
+			wantLine: 2,
+			wantErr:  ErrNoProblemLine,
+		},
+		{
+			desc:     "clause before problem line",
+			input:    "c comment\n1 2 0\np cnf 2 1\n",
+			wantLine: 2,
+			wantErr:  ErrClauseBeforeProblem,
+		},
+		{
+			desc:     "missing count",
+			input:    "p cnf 3\n",
+			wantLine: 1,
+			wantErr:  ErrInvalidProblemLine,
+		},
+		{
+			desc:     "invalid count",
+			input:    "p cnf 3 x\n",
+			wantLine: 1,
+			wantErr:  ErrInvalidProblemLine,
+		},
+		{
+			desc:     "negative count",
+			input:    "p cnf -1 2\n",
+			wantLine: 1,
+			wantErr:  ErrInvalidProblemLine,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotErr := ReadHeader(strings.NewReader(tc.input))
+
+			if tc.wantErr != nil {
+				pe, ok := gotErr.(*ParseError)
+				if !ok || !errors.Is(gotErr, tc.wantErr) {
+					t.Fatalf("ReadHeader(): want error %q, got %v", tc.wantErr, gotErr)
+				}
+				if pe.Line != tc.wantLine {
+					t.Errorf("ReadHeader(): want error on line %d, got %d", tc.wantLine, pe.Line)
+				}
+			} else if gotErr != nil {
+				t.Errorf("ReadHeader(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ReadHeader(): header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
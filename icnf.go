@@ -0,0 +1,102 @@
+package dimacs
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// ICNFBuilder extends Builder to process the assumption lines of incremental
+// CNF (iCNF) files. Such files start with a "p inccnf" problem line and
+// interleave clause lines with assumption lines "a <lit1> <lit2> ... 0" that
+// each introduce a cube of literals to assume.
+//
+// ReadBuilder detects builders implementing ICNFBuilder and passes them the
+// assumption lines, in their order of appearance in the file. Other builders
+// see assumption lines as (malformed) clause lines.
+type ICNFBuilder interface {
+	Builder
+
+	// Assume processes the cube of an assumption line. As for the clauses,
+	// tmpCube is a shared buffer that should only be read from without
+	// being retained.
+	Assume(tmpCube []int) error
+}
+
+// ReadICNFBuilder reads an incremental CNF file from the given reader and
+// populates the given builder. Builder methods are called in the same order
+// as the corresponding lines (i.e. comment, problem, clause, assumption) in
+// the file so that the incremental steps can be replayed in sequence.
+func ReadICNFBuilder(r io.Reader, b ICNFBuilder) error {
+	return ReadBuilder(r, b)
+}
+
+// ICNFFormula is the content of an incremental CNF file as returned by
+// ReadICNF, which Read returns for such files.
+type ICNFFormula struct {
+	Formula     CNFFormula
+	Assumptions [][]int
+}
+
+// ReadICNF parses an incremental CNF file from the given reader and returns
+// the formula made of all its clauses along with its assumption cubes, in
+// order of appearance. Since the "p inccnf" problem line declares no count,
+// the NumVars of the formula is the largest variable used in its clauses.
+// Assumption literals must refer to these variables.
+//
+// Note that ReadICNF loses the interleaving of clauses and assumptions. Use
+// ReadICNFBuilder for the cases where it matters.
+func ReadICNF(r io.Reader) (CNFFormula, [][]int, error) {
+	b := icnfBuilder{}
+	lines, err := readBuilder(context.Background(), r, &b, ReadOptions{})
+	if err != nil {
+		return CNFFormula{}, nil, err
+	}
+	if !b.hasProblem {
+		return CNFFormula{}, nil, newParseError(lines, "", ErrNoProblemLine)
+	}
+
+	f := CNFFormula{NumVars: maxVar(b.clauses), Clauses: b.clauses}
+	if v := maxVar(b.cubes); v > f.NumVars {
+		err := fmt.Errorf("%w: assumption on variable %d, expected variables in 1..%d", ErrVarOutOfRange, v, f.NumVars)
+		return CNFFormula{}, nil, newParseError(lines, "", err)
+	}
+	return f, b.cubes, nil
+}
+
+type icnfBuilder struct {
+	hasProblem bool
+	clauses    [][]int
+	cubes      [][]int
+}
+
+func (b *icnfBuilder) Problem(p string, _ int, _ int) error {
+	if b.hasProblem {
+		return ErrDuplicateProblem
+	}
+	if p != "inccnf" {
+		return fmt.Errorf("%w: expected \"inccnf\" problem, got %q", ErrInvalidProblemType, p)
+	}
+	b.hasProblem = true
+	b.clauses = [][]int{}
+	b.cubes = [][]int{}
+	return nil
+}
+
+func (b *icnfBuilder) Clause(tmp []int) error {
+	if !b.hasProblem {
+		return ErrClauseBeforeProblem
+	}
+	b.clauses = append(b.clauses, append([]int{}, tmp...))
+	return nil
+}
+
+func (b *icnfBuilder) Assume(tmp []int) error {
+	if !b.hasProblem {
+		return fmt.Errorf("assumption found before problem line")
+	}
+	b.cubes = append(b.cubes, append([]int{}, tmp...))
+	return nil
+}
+
+func (b *icnfBuilder) Comment(_ string) error { return nil } // ignore comments
@@ -0,0 +1,173 @@
+package dimacs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const validICNF = `c incremental
+p inccnf
+1 2 0
+a -1 0
+-2 3 0
+a -3 0
+a 0
+`
+
+// eventRecorder records the calls made to an ICNFBuilder as strings.
+type eventRecorder struct {
+	events []string
+}
+
+func (er *eventRecorder) Problem(p string, v int, c int) error {
+	er.events = append(er.events, fmt.Sprintf("p %s %d %d", p, v, c))
+	return nil
+}
+
+func (er *eventRecorder) Clause(tmp []int) error {
+	er.events = append(er.events, fmt.Sprintf("clause %v", tmp))
+	return nil
+}
+
+func (er *eventRecorder) Assume(tmp []int) error {
+	er.events = append(er.events, fmt.Sprintf("assume %v", tmp))
+	return nil
+}
+
+func (er *eventRecorder) Comment(line string) error {
+	er.events = append(er.events, line)
+	return nil
+}
+
+func TestReadICNFBuilder(t *testing.T) {
+	er := &eventRecorder{}
+
+	if err := ReadICNFBuilder(strings.NewReader(validICNF), er); err != nil {
+		t.Fatalf("ReadICNFBuilder(): want no error, got %s", err)
+	}
+
+	want := []string{
+		"c incremental",
+		"p inccnf -1 -1",
+		"clause [1 2]",
+		"assume [-1]",
+		"clause [-2 3]",
+		"assume [-3]",
+		"assume []",
+	}
+	if diff := cmp.Diff(want, er.events); diff != "" {
+		t.Errorf("ReadICNFBuilder(): events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadICNFBuilder_errors(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		input string
+	}{
+		{
+			desc:  "invalid assumption literal",
+			input: "p inccnf\na 1 x 0\n",
+		},
+		{
+			desc:  "zero in assumption",
+			input: "p inccnf\na 1 0 2 0\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := ReadICNFBuilder(strings.NewReader(tc.input), &eventRecorder{}); err == nil {
+				t.Errorf("ReadICNFBuilder(): want error, got nil")
+			}
+		})
+	}
+}
+
+func TestReadBuilder_assumptionsWithoutICNFBuilder(t *testing.T) {
+	err := ReadBuilder(strings.NewReader(validICNF), &testBuilder{})
+
+	if err == nil {
+		t.Errorf("ReadBuilder(): want error, got nil")
+	}
+}
+
+func TestReadICNF(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		input     string
+		wantCNF   CNFFormula
+		wantCubes [][]int
+		wantErr   bool
+	}{
+		{
+			desc:  "valid icnf",
+			input: validICNF,
+			wantCNF: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{1, 2}, {-2, 3}},
+			},
+			wantCubes: [][]int{{-1}, {-3}, {}},
+			wantErr:   false,
+		},
+		{
+			desc:      "no clauses",
+			input:     "p inccnf\n",
+			wantCNF:   CNFFormula{NumVars: 0, Clauses: [][]int{}},
+			wantCubes: [][]int{},
+			wantErr:   false,
+		},
+		{
+			desc:    "missing problem line",
+			input:   "c comment\n",
+			wantErr: true,
+		},
+		{
+			desc:    "cnf problem line",
+			input:   "p cnf 2 1\n1 2 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "duplicate problem line",
+			input:   "p inccnf\np inccnf\n",
+			wantErr: true,
+		},
+		{
+			desc:    "clause before problem line",
+			input:   "1 2 0\np inccnf\n",
+			wantErr: true,
+		},
+		{
+			desc:    "assumption before problem line",
+			input:   "a 1 0\np inccnf\n",
+			wantErr: true,
+		},
+		{
+			desc:    "assumption out of range",
+			input:   "p inccnf\n1 2 0\na -3 0\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotCNF, gotCubes, gotErr := ReadICNF(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadICNF(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadICNF(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantCNF, gotCNF); diff != "" {
+				t.Errorf("ReadICNF(): CNF mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantCubes, gotCubes); diff != "" {
+				t.Errorf("ReadICNF(): cubes mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package dimacs
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonCNF is the JSON representation of a CNFFormula.
+type jsonCNF struct {
+	NumVars int     `json:"num_vars"`
+	Clauses [][]int `json:"clauses"`
+}
+
+// MarshalJSON encodes the formula as a JSON object of the form
+// {"num_vars":3,"clauses":[[1,-2],[3]]}. A formula without clauses has an
+// empty array of clauses (rather than null).
+func (f CNFFormula) MarshalJSON() ([]byte, error) {
+	clauses := f.Clauses
+	if clauses == nil {
+		clauses = [][]int{}
+	}
+	return json.Marshal(jsonCNF{NumVars: f.NumVars, Clauses: clauses})
+}
+
+// UnmarshalJSON decodes a formula encoded by MarshalJSON. An error is returned
+// if the number of variables is negative, if a clause contains a 0, or if a
+// clause contains a variable greater than the number of variables (the error
+// then wraps ErrVarOutOfRange).
+func (f *CNFFormula) UnmarshalJSON(data []byte) error {
+	var jf jsonCNF
+	if err := json.Unmarshal(data, &jf); err != nil {
+		return err
+	}
+	if err := checkJSONClauses(jf.NumVars, jf.Clauses); err != nil {
+		return err
+	}
+	if jf.Clauses == nil {
+		jf.Clauses = [][]int{}
+	}
+	*f = CNFFormula{NumVars: jf.NumVars, Clauses: jf.Clauses}
+	return nil
+}
+
+// jsonXorCNF is the JSON representation of an XorCNFFormula.
+type jsonXorCNF struct {
+	NumVars    int     `json:"num_vars"`
+	Clauses    [][]int `json:"clauses"`
+	XorClauses [][]int `json:"xor_clauses"`
+}
+
+// MarshalJSON encodes the formula as CNFFormula.MarshalJSON does with an
+// additional array of xor clauses, e.g.
+// {"num_vars":3,"clauses":[[1,-2]],"xor_clauses":[[2,3]]}.
+func (f XorCNFFormula) MarshalJSON() ([]byte, error) {
+	jf := jsonXorCNF{NumVars: f.NumVars, Clauses: f.Clauses, XorClauses: f.XorClauses}
+	if jf.Clauses == nil {
+		jf.Clauses = [][]int{}
+	}
+	if jf.XorClauses == nil {
+		jf.XorClauses = [][]int{}
+	}
+	return json.Marshal(jf)
+}
+
+// UnmarshalJSON decodes a formula encoded by MarshalJSON. Both clauses and xor
+// clauses are validated as in CNFFormula.UnmarshalJSON.
+func (f *XorCNFFormula) UnmarshalJSON(data []byte) error {
+	var jf jsonXorCNF
+	if err := json.Unmarshal(data, &jf); err != nil {
+		return err
+	}
+	if err := checkJSONClauses(jf.NumVars, jf.Clauses); err != nil {
+		return err
+	}
+	if err := checkJSONClauses(jf.NumVars, jf.XorClauses); err != nil {
+		return err
+	}
+	if jf.Clauses == nil {
+		jf.Clauses = [][]int{}
+	}
+	if jf.XorClauses == nil {
+		jf.XorClauses = [][]int{}
+	}
+	*f = XorCNFFormula{
+		CNFFormula: CNFFormula{NumVars: jf.NumVars, Clauses: jf.Clauses},
+		XorClauses: jf.XorClauses,
+	}
+	return nil
+}
+
+// checkJSONClauses returns an error if nVars is negative or if the clauses
+// contain a 0 or a variable greater than nVars.
+func checkJSONClauses(nVars int, clauses [][]int) error {
+	if nVars < 0 {
+		return fmt.Errorf("number of variables must be non-negative, got: %d", nVars)
+	}
+	for _, c := range clauses {
+		for _, l := range c {
+			if l == 0 {
+				return fmt.Errorf("invalid literal 0 in clause %v", c)
+			}
+			if abs(l) > nVars {
+				return fmt.Errorf("%w: literal %d, expected variables in 1..%d", ErrVarOutOfRange, l, nVars)
+			}
+		}
+	}
+	return nil
+}
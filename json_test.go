@@ -0,0 +1,217 @@
+package dimacs
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCNFFormula_MarshalJSON(t *testing.T) {
+	testCases := []struct {
+		desc string
+		f    CNFFormula
+		want string
+	}{
+		{
+			desc: "formula",
+			f:    testFormula,
+			want: `{"num_vars":3,"clauses":[[1,2,3],[1,-2,3],[1,-3],[-2,-3]]}`,
+		},
+		{
+			desc: "empty clause",
+			f:    CNFFormula{NumVars: 1, Clauses: [][]int{{}, {1}}},
+			want: `{"num_vars":1,"clauses":[[],[1]]}`,
+		},
+		{
+			desc: "no clauses",
+			f:    CNFFormula{NumVars: 2},
+			want: `{"num_vars":2,"clauses":[]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := json.Marshal(tc.f)
+
+			if err != nil {
+				t.Fatalf("json.Marshal(): want no error, got %s", err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("json.Marshal(): output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCNFFormula_UnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		want    CNFFormula
+		wantErr bool
+		wantIs  error // sentinel error wrapped by the error, if any
+	}{
+		{
+			desc:  "formula",
+			input: `{"num_vars":3,"clauses":[[1,2,3],[1,-2,3],[1,-3],[-2,-3]]}`,
+			want:  testFormula,
+		},
+		{
+			desc:  "no clauses",
+			input: `{"num_vars":2}`,
+			want:  CNFFormula{NumVars: 2, Clauses: [][]int{}},
+		},
+		{
+			desc:    "zero literal",
+			input:   `{"num_vars":3,"clauses":[[1,0,2]]}`,
+			wantErr: true,
+		},
+		{
+			desc:    "variable out of range",
+			input:   `{"num_vars":3,"clauses":[[1,-4]]}`,
+			wantErr: true,
+			wantIs:  ErrVarOutOfRange,
+		},
+		{
+			desc:    "negative number of variables",
+			input:   `{"num_vars":-1,"clauses":[]}`,
+			wantErr: true,
+		},
+		{
+			desc:    "invalid JSON",
+			input:   `{"num_vars":3,"clauses":[[1,"2"]]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := CNFFormula{}
+			gotErr := json.Unmarshal([]byte(tc.input), &got)
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("json.Unmarshal(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("json.Unmarshal(): want no error, got %s", gotErr)
+			}
+			if tc.wantIs != nil && !errors.Is(gotErr, tc.wantIs) {
+				t.Errorf("json.Unmarshal(): want error %q, got %v", tc.wantIs, gotErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("json.Unmarshal(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCNFFormula_JSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(testFormula)
+	if err != nil {
+		t.Fatalf("json.Marshal(): want no error, got %s", err)
+	}
+
+	got := CNFFormula{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff(testFormula, got); diff != "" {
+		t.Errorf("round trip: CNF mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestXorCNFFormula_MarshalJSON(t *testing.T) {
+	testCases := []struct {
+		desc string
+		f    XorCNFFormula
+		want string
+	}{
+		{
+			desc: "formula",
+			f: XorCNFFormula{
+				CNFFormula: CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}}},
+				XorClauses: [][]int{{2, 3}},
+			},
+			want: `{"num_vars":3,"clauses":[[1,-2]],"xor_clauses":[[2,3]]}`,
+		},
+		{
+			desc: "no clauses",
+			f:    XorCNFFormula{CNFFormula: CNFFormula{NumVars: 2}},
+			want: `{"num_vars":2,"clauses":[],"xor_clauses":[]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := json.Marshal(tc.f)
+
+			if err != nil {
+				t.Fatalf("json.Marshal(): want no error, got %s", err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("json.Marshal(): output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestXorCNFFormula_UnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		want    XorCNFFormula
+		wantErr bool
+		wantIs  error // sentinel error wrapped by the error, if any
+	}{
+		{
+			desc:  "formula",
+			input: `{"num_vars":3,"clauses":[[1,-2]],"xor_clauses":[[2,3]]}`,
+			want: XorCNFFormula{
+				CNFFormula: CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}}},
+				XorClauses: [][]int{{2, 3}},
+			},
+		},
+		{
+			desc:  "no xor clauses",
+			input: `{"num_vars":2,"clauses":[[1]]}`,
+			want: XorCNFFormula{
+				CNFFormula: CNFFormula{NumVars: 2, Clauses: [][]int{{1}}},
+				XorClauses: [][]int{},
+			},
+		},
+		{
+			desc:    "zero literal in xor clause",
+			input:   `{"num_vars":3,"xor_clauses":[[1,0]]}`,
+			wantErr: true,
+		},
+		{
+			desc:    "variable out of range in xor clause",
+			input:   `{"num_vars":3,"xor_clauses":[[1,-4]]}`,
+			wantErr: true,
+			wantIs:  ErrVarOutOfRange,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := XorCNFFormula{}
+			gotErr := json.Unmarshal([]byte(tc.input), &got)
+
+			if tc.wantErr && gotErr == nil {
+				t.Fatalf("json.Unmarshal(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Fatalf("json.Unmarshal(): want no error, got %s", gotErr)
+			}
+			if tc.wantIs != nil && !errors.Is(gotErr, tc.wantIs) {
+				t.Errorf("json.Unmarshal(): want error %q, got %v", tc.wantIs, gotErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("json.Unmarshal(): formula mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package dimacs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// LRATStep is a step of a proof in the LRAT format. An addition step adds the
+// clause made of Literals with identifier ID, Antecedents being the hints that
+// justify it (the identifiers of the clauses used to derive it, negative for
+// RAT hints). A deletion step (Delete set to true) deletes the clauses whose
+// identifiers are in Deleted.
+type LRATStep struct {
+	ID          int
+	Delete      bool
+	Literals    []int
+	Antecedents []int
+	Deleted     []int
+}
+
+// ReadLRAT parses a proof in the textual LRAT format from the given reader and
+// returns its steps in order. Addition lines are made of the identifier of the
+// clause, its literals terminated by a 0, and its antecedents terminated by a
+// 0 (e.g. "5 1 -2 0 3 -1 4 0"). Deletion lines are made of an identifier
+// followed by "d" and the identifiers of the deleted clauses terminated by a 0
+// (e.g. "5 d 1 3 0"). Comment lines (starting with "c") are ignored. The
+// binary LRAT format is not supported.
+//
+// Clause identifiers must be positive and the identifiers of the added
+// clauses must be strictly increasing. An error is returned otherwise.
+func ReadLRAT(r io.Reader) ([]LRATStep, error) {
+	rd := Reader{}
+	rd.Reset(r)
+	steps := []LRATStep{}
+	lastID := 0 // identifier of the last added clause
+
+	for n := 1; ; n++ {
+		line, err := rd.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n == 1 {
+			line = bytes.TrimPrefix(line, []byte(utf8BOM))
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == 'c' {
+			continue
+		}
+
+		step, err := parseLRATLine(line)
+		if err == nil && !step.Delete {
+			if step.ID <= lastID {
+				err = fmt.Errorf("clause identifier %d is not greater than previous identifier %d", step.ID, lastID)
+			}
+			lastID = step.ID
+		}
+		if err != nil {
+			return nil, newParseError(n, string(line), err)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// parseLRATLine parses an addition or deletion line of an LRAT proof. The
+// lists of integers following the identifier (and the "d" of deletion lines)
+// are parsed as clause lines, each terminated by a 0.
+func parseLRATLine(line []byte) (LRATStep, error) {
+	tok, rest := nextToken(line)
+	id, err := strconv.Atoi(string(tok))
+	if err != nil || id <= 0 {
+		return LRATStep{}, fmt.Errorf("invalid clause identifier %q", tok)
+	}
+	step := LRATStep{ID: id}
+
+	if tok, deleted := nextToken(rest); string(tok) == "d" {
+		step.Delete = true
+		if step.Deleted, err = parseLRATList(deleted, "deleted clause identifiers"); err != nil {
+			return LRATStep{}, err
+		}
+		for _, id := range step.Deleted {
+			if id <= 0 {
+				return LRATStep{}, fmt.Errorf("invalid deleted clause identifier %d", id)
+			}
+		}
+		return step, nil
+	}
+
+	lits, antecedents := rest, []byte(nil)
+	for rem := rest; len(rem) > 0; {
+		if tok, rem = nextToken(rem); string(tok) == "0" {
+			lits, antecedents = rest[:len(rest)-len(rem)], rem
+			break
+		}
+	}
+	if step.Literals, err = parseLRATList(lits, "literals"); err != nil {
+		return LRATStep{}, err
+	}
+	if step.Antecedents, err = parseLRATList(antecedents, "antecedents"); err != nil {
+		return LRATStep{}, err
+	}
+	return step, nil
+}
+
+// parseLRATList parses a list of integers terminated by a 0, which must be the
+// last token of list.
+func parseLRATList(list []byte, what string) ([]int, error) {
+	ints, terminated, err := parseClause(list, []int{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", what, err)
+	}
+	if !terminated {
+		return nil, fmt.Errorf("%w: %s", ErrUnterminatedClause, what)
+	}
+	return ints, nil
+}
+
+// nextToken returns the first space-separated token of b and the bytes that
+// follow it.
+func nextToken(b []byte) (tok []byte, rest []byte) {
+	i := 0
+	for i < len(b) && isSpace(b[i]) {
+		i++
+	}
+	j := i
+	for j < len(b) && !isSpace(b[j]) {
+		j++
+	}
+	return b[i:j], b[j:]
+}
@@ -0,0 +1,123 @@
+package dimacs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadLRAT(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		input    string
+		want     []LRATStep
+		wantLine int
+		wantErr  bool
+	}{
+		{
+			desc:  "byte order mark",
+			input: "\ufeff5 1 0 2 0\n",
+			want:  []LRATStep{{ID: 5, Literals: []int{1}, Antecedents: []int{2}}},
+		},
+		{
+			desc:  "empty proof",
+			input: "",
+			want:  []LRATStep{},
+		},
+		{
+			desc:  "proof",
+			input: "c lrat proof\n5 1 -2 0 3 -1 4 0\n5 d 1 3 0\n\n6 0 5 2 0\n",
+			want: []LRATStep{
+				{ID: 5, Literals: []int{1, -2}, Antecedents: []int{3, -1, 4}},
+				{ID: 5, Delete: true, Deleted: []int{1, 3}},
+				{ID: 6, Literals: []int{}, Antecedents: []int{5, 2}},
+			},
+		},
+		{
+			desc:  "tab separated",
+			input: "7\t-1\t0\t2\t0\n",
+			want:  []LRATStep{{ID: 7, Literals: []int{-1}, Antecedents: []int{2}}},
+		},
+		{
+			desc:  "no antecedents",
+			input: "3 2 0 0\n",
+			want:  []LRATStep{{ID: 3, Literals: []int{2}, Antecedents: []int{}}},
+		},
+		{
+			desc:     "decreasing identifiers",
+			input:    "5 1 0 1 0\n4 -1 0 2 0\n",
+			wantLine: 2,
+			wantErr:  true,
+		},
+		{
+			desc:     "repeated identifier",
+			input:    "5 1 0 1 0\n5 d 1 0\n5 -1 0 2 0\n",
+			wantLine: 3,
+			wantErr:  true,
+		},
+		{
+			desc:     "invalid identifier",
+			input:    "0 1 0 1 0\n",
+			wantLine: 1,
+			wantErr:  true,
+		},
+		{
+			desc:     "missing antecedents terminator",
+			input:    "5 1 0 1 2\n",
+			wantLine: 1,
+			wantErr:  true,
+		},
+		{
+			desc:     "missing literals terminator",
+			input:    "5 1 -2\n",
+			wantLine: 1,
+			wantErr:  true,
+		},
+		{
+			desc:     "tokens after the last 0",
+			input:    "5 1 0 2 0 3\n",
+			wantLine: 1,
+			wantErr:  true,
+		},
+		{
+			desc:     "missing deletion terminator",
+			input:    "5 d 1 3\n",
+			wantLine: 1,
+			wantErr:  true,
+		},
+		{
+			desc:     "invalid deleted identifier",
+			input:    "5 d 1 -3 0\n",
+			wantLine: 1,
+			wantErr:  true,
+		},
+		{
+			desc:     "invalid literal",
+			input:    "c\n5 1 x 0 1 0\n",
+			wantLine: 2,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotErr := ReadLRAT(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Fatalf("ReadLRAT(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Fatalf("ReadLRAT(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ReadLRAT(): steps mismatch (-want +got):\n%s", diff)
+			}
+			var pe *ParseError
+			if tc.wantErr && (!errors.As(gotErr, &pe) || pe.Line != tc.wantLine) {
+				t.Errorf("ReadLRAT(): want error on line %d, got %v", tc.wantLine, gotErr)
+			}
+		})
+	}
+}
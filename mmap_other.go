@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package dimacs
+
+// ReadCNFMmap is like ReadCNFFile. Memory mapping is not supported on this
+// platform.
+func ReadCNFMmap(name string) (CNFFormula, error) {
+	return ReadCNFFile(name)
+}
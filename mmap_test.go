@@ -0,0 +1,79 @@
+package dimacs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadCNFMmap(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.cnf")
+	compressed := filepath.Join(dir, "compressed.cnf.gz")
+	empty := filepath.Join(dir, "empty.cnf")
+	if err := os.WriteFile(plain, []byte(testFormulaDIMACS), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(compressed, gzipped(t, testFormulaDIMACS), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc    string
+		name    string
+		wantCNF CNFFormula
+		wantErr bool
+	}{
+		{
+			desc:    "plain file",
+			name:    plain,
+			wantCNF: testFormula,
+			wantErr: false,
+		},
+		{
+			desc:    "gzipped file",
+			name:    compressed,
+			wantCNF: testFormula,
+			wantErr: false,
+		},
+		{
+			desc:    "empty file",
+			name:    empty,
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:    "directory",
+			name:    dir,
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+		{
+			desc:    "missing file",
+			name:    filepath.Join(dir, "missing.cnf"),
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotCNF, gotErr := ReadCNFMmap(tc.name)
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadCNFMmap(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadCNFMmap(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantCNF, gotCNF); diff != "" {
+				t.Errorf("ReadCNFMmap(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package dimacs
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// ReadCNFMmap is like ReadCNFFile but memory-maps the named file and parses it
+// directly from memory, which avoids read system calls and buffer copies for
+// very large files. Compressed files are decompressed from the mapped memory
+// through a buffered reader and thus do not benefit from this. The file must
+// be a regular file. The returned formula does not reference the mapped
+// memory, which is unmapped before returning.
+//
+// On platforms where memory mapping is not supported, ReadCNFMmap falls back
+// to ReadCNFFile.
+func ReadCNFMmap(name string) (CNFFormula, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return CNFFormula{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return CNFFormula{}, err
+	}
+	if !info.Mode().IsRegular() {
+		return CNFFormula{}, fmt.Errorf("cannot memory-map %s: not a regular file", name)
+	}
+	size := info.Size()
+	if size == 0 {
+		return ReadCNF(bytes.NewReader(nil)) // empty files cannot be mapped
+	}
+	if int64(int(size)) != size {
+		return CNFFormula{}, fmt.Errorf("cannot memory-map %s: file too large", name)
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return CNFFormula{}, fmt.Errorf("cannot memory-map %s: %w", name, err)
+	}
+	defer syscall.Munmap(data)
+
+	// The clauses are copied out of the mapped memory while parsing.
+	if !isCompressed(data) {
+		return ReadCNFBytes(data)
+	}
+	r, err := decompress(bytes.NewReader(data))
+	if err != nil {
+		return CNFFormula{}, err
+	}
+	return ReadCNF(r)
+}
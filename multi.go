@@ -0,0 +1,54 @@
+package dimacs
+
+import (
+	"context"
+	"io"
+)
+
+// ReadAllCNF parses and returns all the DIMACS CNF formulas from the given
+// reader. Unlike ReadCNF, which rejects duplicate problem lines, each problem
+// line starts a new formula made of the clauses that follow it. The number of
+// clauses of each formula is validated independently against the count
+// declared in its own problem line.
+//
+// Comment lines and the "%" end marker are handled as in ReadBuilder, the end
+// marker ending the last formula. An error is returned if the input does not
+// contain any problem line.
+func ReadAllCNF(r io.Reader) ([]CNFFormula, error) {
+	b := multiCNFBuilder{formulas: []CNFFormula{}}
+	lines, err := readBuilder(context.Background(), r, &b, ReadOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if err := b.next(); err != nil {
+		return nil, newParseError(lines, "", err)
+	}
+	return b.formulas, nil
+}
+
+// multiCNFBuilder collects the formulas of a multi-instance DIMACS file.
+type multiCNFBuilder struct {
+	cnfBuilder
+	formulas []CNFFormula
+}
+
+func (b *multiCNFBuilder) Problem(p string, v int, c int) error {
+	if b.cnf != nil {
+		if err := b.next(); err != nil {
+			return err
+		}
+	}
+	return b.cnfBuilder.Problem(p, v, c)
+}
+
+// next validates and collects the current formula, and resets the builder to
+// read the next one.
+func (b *multiCNFBuilder) next() error {
+	f, err := b.formula()
+	if err != nil {
+		return err
+	}
+	b.formulas = append(b.formulas, f)
+	b.cnfBuilder = cnfBuilder{}
+	return nil
+}
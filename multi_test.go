@@ -0,0 +1,102 @@
+package dimacs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const validMultiCNF = `c first formula
+p cnf 3 2
+1 -2 0
+2 3 0
+c second formula
+p cnf 2 1
+-1 -2 0
+p cnf 0 0
+`
+
+func TestReadAllCNF(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		input        string
+		wantFormulas []CNFFormula
+		wantErr      bool
+	}{
+		{
+			desc:  "several formulas",
+			input: validMultiCNF,
+			wantFormulas: []CNFFormula{
+				{NumVars: 3, Clauses: [][]int{{1, -2}, {2, 3}}},
+				{NumVars: 2, Clauses: [][]int{{-1, -2}}},
+				{NumVars: 0, Clauses: [][]int{}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "single formula",
+			input: validCNF_manyComments,
+			wantFormulas: []CNFFormula{
+				{NumVars: 3, Clauses: [][]int{{1, 2, 3}, {1, -2, 3}, {1, -3}, {-2, -3}}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "no problem line",
+			input:   "c comment\n",
+			wantErr: true,
+		},
+		{
+			desc:    "missing clauses in first formula",
+			input:   "p cnf 2 2\n1 2 0\np cnf 2 1\n1 2 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "missing clauses in last formula",
+			input:   "p cnf 2 1\n1 2 0\np cnf 2 2\n1 2 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "too many clauses",
+			input:   "p cnf 2 1\n1 2 0\n-1 0\np cnf 2 1\n1 2 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "clause before first problem line",
+			input:   "1 2 0\np cnf 2 1\n1 2 0\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotErr := ReadAllCNF(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadAllCNF(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadAllCNF(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantFormulas, got); diff != "" {
+				t.Errorf("ReadAllCNF(): formulas mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadAllCNF_errorLine(t *testing.T) {
+	input := "p cnf 2 2\n1 2 0\np cnf 2 1\n1 2 0\n"
+
+	_, err := ReadAllCNF(strings.NewReader(input))
+
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Line != 3 {
+		t.Errorf("ReadAllCNF(): want *ParseError at line 3, got %v", err)
+	}
+	if !errors.Is(err, ErrMissingClauses) {
+		t.Errorf("ReadAllCNF(): want error %s, got %v", ErrMissingClauses, err)
+	}
+}
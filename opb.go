@@ -0,0 +1,264 @@
+package dimacs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// OPBTerm is a weighted literal of a pseudo-Boolean expression. Literals follow
+// the DIMACS convention: Lit is i for variable x<i> and -i for its negation
+// ~x<i>.
+type OPBTerm struct {
+	Coef int
+	Lit  int
+}
+
+// OPBRelation is the relational operator of a pseudo-Boolean constraint.
+type OPBRelation string
+
+const (
+	OPBGreaterOrEqual OPBRelation = ">="
+	OPBLessOrEqual    OPBRelation = "<="
+	OPBEqual          OPBRelation = "="
+)
+
+// OPBConstraint represents the linear pseudo-Boolean constraint
+// sum(Terms) <Relation> RHS.
+type OPBConstraint struct {
+	Terms    []OPBTerm
+	Relation OPBRelation
+	RHS      int
+}
+
+// OPBInstance represents a pseudo-Boolean problem in the OPB format.
+type OPBInstance struct {
+	// NumVars and NumConstraints are the counts declared in the optional
+	// "* #variable= N #constraint= M" header comment, or the counts inferred
+	// from the instance if the header is missing.
+	NumVars        int
+	NumConstraints int
+
+	// Objective holds the terms of the "min:" objective function to minimize.
+	// It is nil if the instance has no objective.
+	Objective []OPBTerm
+
+	Constraints []OPBConstraint
+}
+
+// ReadOPB parses and returns a pseudo-Boolean instance in the OPB format from
+// the given reader. Comment lines start with "*" and every statement (the
+// objective and the constraints) must be terminated by ";". Statements can
+// span several lines. Non-linear terms (products of literals) are not
+// supported. The optional "* #variable= N #constraint= M" header is only
+// recognized on the first non-empty line.
+func ReadOPB(r io.Reader) (OPBInstance, error) {
+	rd := Reader{}
+	rd.Reset(r)
+	opb := OPBInstance{}
+	hasHeader := false
+	firstLine := true // true until the first non-empty line
+	var stmt []string // tokens of the current statement
+
+	n := 1
+	for ; ; n++ {
+		b, err := rd.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return OPBInstance{}, err
+		}
+		if n == 1 {
+			b = bytes.TrimPrefix(b, []byte(utf8BOM))
+		}
+		line := strings.TrimSpace(string(b))
+		if line == "" {
+			continue
+		}
+		isFirst := firstLine
+		firstLine = false
+		if line[0] == '*' {
+			if isFirst && strings.Contains(line, "#variable=") {
+				nVars, nConstraints, err := parseOPBHeader(line)
+				if err != nil {
+					return OPBInstance{}, newParseError(n, line, err)
+				}
+				opb.NumVars, opb.NumConstraints = nVars, nConstraints
+				hasHeader = true
+			}
+			continue
+		}
+		for _, tok := range strings.Fields(line) {
+			end := strings.HasSuffix(tok, ";")
+			if tok = strings.TrimSuffix(tok, ";"); tok != "" {
+				stmt = append(stmt, tok)
+			}
+			if !end {
+				continue
+			}
+			if err := opb.addStatement(stmt); err != nil {
+				return OPBInstance{}, newParseError(n, line, err)
+			}
+			stmt = stmt[:0]
+		}
+	}
+	lastLine := n - 1
+	if len(stmt) != 0 {
+		err := fmt.Errorf("statement not terminated by \";\": %s", strings.Join(stmt, " "))
+		return OPBInstance{}, newParseError(lastLine, "", err)
+	}
+
+	maxVar := 0
+	for _, c := range opb.Constraints {
+		maxVar = maxTermVar(maxVar, c.Terms)
+	}
+	maxVar = maxTermVar(maxVar, opb.Objective)
+
+	if !hasHeader {
+		opb.NumVars = maxVar
+		opb.NumConstraints = len(opb.Constraints)
+		return opb, nil
+	}
+	if maxVar > opb.NumVars {
+		err := fmt.Errorf("%w: variable x%d, %d variables declared", ErrVarOutOfRange, maxVar, opb.NumVars)
+		return OPBInstance{}, newParseError(lastLine, "", err)
+	}
+	if got := len(opb.Constraints); got != opb.NumConstraints {
+		sentinel := ErrMissingClauses
+		if got > opb.NumConstraints {
+			sentinel = ErrTooManyClauses
+		}
+		err := fmt.Errorf("%w: expected %d constraints, got %d", sentinel, opb.NumConstraints, got)
+		return OPBInstance{}, newParseError(lastLine, "", err)
+	}
+	return opb, nil
+}
+
+// parseOPBHeader parses a "* #variable= N #constraint= M" header comment.
+func parseOPBHeader(line string) (int, int, error) {
+	fields := strings.Fields(line)
+	nVars, nConstraints := -1, -1
+	for i := 0; i < len(fields)-1; i++ {
+		var dst *int
+		switch fields[i] {
+		case "#variable=":
+			dst = &nVars
+		case "#constraint=":
+			dst = &nConstraints
+		default:
+			continue
+		}
+		v, err := strconv.Atoi(fields[i+1])
+		if err != nil || v < 0 {
+			return 0, 0, fmt.Errorf("%w: invalid header count %q", ErrInvalidProblemLine, fields[i+1])
+		}
+		*dst = v
+	}
+	if nVars < 0 || nConstraints < 0 {
+		return 0, 0, fmt.Errorf("%w: header should declare #variable= and #constraint=: %s", ErrInvalidProblemLine, line)
+	}
+	return nVars, nConstraints, nil
+}
+
+// addStatement parses the tokens of a statement (without the ";" terminator)
+// and adds the resulting objective or constraint to the instance.
+func (opb *OPBInstance) addStatement(tokens []string) error {
+	if len(tokens) > 0 && tokens[0] == "min:" {
+		if opb.Objective != nil {
+			return fmt.Errorf("duplicate objective")
+		}
+		if len(opb.Constraints) > 0 {
+			return fmt.Errorf("objective found after constraints")
+		}
+		terms, rest, err := parseOPBTerms(tokens[1:])
+		if err != nil {
+			return err
+		}
+		if len(rest) != 0 {
+			return fmt.Errorf("unexpected token %q in objective", rest[0])
+		}
+		opb.Objective = append([]OPBTerm{}, terms...)
+		return nil
+	}
+
+	terms, rest, err := parseOPBTerms(tokens)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 2 {
+		return fmt.Errorf("constraint should end with a relation and a right-hand side: %s", strings.Join(tokens, " "))
+	}
+	rel := OPBRelation(rest[0])
+	switch rel {
+	case OPBGreaterOrEqual, OPBLessOrEqual, OPBEqual:
+	default:
+		return fmt.Errorf("invalid relational operator %q", rest[0])
+	}
+	rhs, err := strconv.Atoi(rest[1])
+	if err != nil {
+		return fmt.Errorf("invalid right-hand side: %w", err)
+	}
+	opb.Constraints = append(opb.Constraints, OPBConstraint{
+		Terms:    terms,
+		Relation: rel,
+		RHS:      rhs,
+	})
+	return nil
+}
+
+// parseOPBTerms parses the leading "<coef> <literal>" pairs of tokens and
+// returns them along with the remaining tokens.
+func parseOPBTerms(tokens []string) ([]OPBTerm, []string, error) {
+	terms := []OPBTerm{}
+	for len(tokens) >= 2 && !isOPBRelation(tokens[0]) {
+		coef, err := strconv.Atoi(tokens[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid coefficient: %w", err)
+		}
+		lit, err := parseOPBLiteral(tokens[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		terms = append(terms, OPBTerm{Coef: coef, Lit: lit})
+		tokens = tokens[2:]
+	}
+	return terms, tokens, nil
+}
+
+// parseOPBLiteral parses literals of the form "x<i>" and "~x<i>".
+func parseOPBLiteral(tok string) (int, error) {
+	sign := 1
+	s := tok
+	if strings.HasPrefix(s, "~") {
+		sign = -1
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "x") {
+		return 0, fmt.Errorf("invalid literal %q", tok)
+	}
+	v, err := strconv.Atoi(s[1:])
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("invalid literal %q", tok)
+	}
+	return sign * v, nil
+}
+
+func isOPBRelation(tok string) bool {
+	switch OPBRelation(tok) {
+	case OPBGreaterOrEqual, OPBLessOrEqual, OPBEqual:
+		return true
+	}
+	return false
+}
+
+func maxTermVar(maxVar int, terms []OPBTerm) int {
+	for _, t := range terms {
+		if v := abs(t.Lit); v > maxVar {
+			maxVar = v
+		}
+	}
+	return maxVar
+}
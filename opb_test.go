@@ -0,0 +1,182 @@
+package dimacs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const validOPB = `* #variable= 3 #constraint= 3
+* a comment
+min: +1 x1 +2 x2 -1 ~x3 ;
++3 x1 -2 x2 >= 1 ;
++1 x1 +1 x2
++1 x3 = 2;
+-1 ~x1 <= 0 ;
+`
+
+func TestReadOPB(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		wantOPB OPBInstance
+		wantErr bool
+	}{
+		{
+			desc:  "valid instance",
+			input: validOPB,
+			wantOPB: OPBInstance{
+				NumVars:        3,
+				NumConstraints: 3,
+				Objective:      []OPBTerm{{1, 1}, {2, 2}, {-1, -3}},
+				Constraints: []OPBConstraint{
+					{Terms: []OPBTerm{{3, 1}, {-2, 2}}, Relation: OPBGreaterOrEqual, RHS: 1},
+					{Terms: []OPBTerm{{1, 1}, {1, 2}, {1, 3}}, Relation: OPBEqual, RHS: 2},
+					{Terms: []OPBTerm{{-1, -1}}, Relation: OPBLessOrEqual, RHS: 0},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "no header and no objective",
+			input: "+1 x4 +1 x2 >= 1 ;\n",
+			wantOPB: OPBInstance{
+				NumVars:        4,
+				NumConstraints: 1,
+				Constraints: []OPBConstraint{
+					{Terms: []OPBTerm{{1, 4}, {1, 2}}, Relation: OPBGreaterOrEqual, RHS: 1},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "comment within statement",
+			input: "min: +1 x1\n* a comment\n+1 x2 ;\n+1 x1 +1 x2 >= 1 ;\n",
+			wantOPB: OPBInstance{
+				NumVars:        2,
+				NumConstraints: 1,
+				Objective:      []OPBTerm{{1, 1}, {1, 2}},
+				Constraints: []OPBConstraint{
+					{Terms: []OPBTerm{{1, 1}, {1, 2}}, Relation: OPBGreaterOrEqual, RHS: 1},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "missing semicolon",
+			input:   "+1 x1 >= 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid relation",
+			input:   "+1 x1 > 1 ;\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid literal",
+			input:   "+1 y1 >= 1 ;\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid coefficient",
+			input:   "a x1 >= 1 ;\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid right-hand side",
+			input:   "+1 x1 >= b ;\n",
+			wantErr: true,
+		},
+		{
+			desc:    "variable out of range",
+			input:   "* #variable= 1 #constraint= 1\n+1 x2 >= 1 ;\n",
+			wantErr: true,
+		},
+		{
+			desc:    "constraint count mismatch",
+			input:   "* #variable= 1 #constraint= 2\n+1 x1 >= 1 ;\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid header",
+			input:   "* #variable= a #constraint= 2\n+1 x1 >= 1 ;\n",
+			wantErr: true,
+		},
+		{
+			desc:    "objective after constraints",
+			input:   "+1 x1 >= 1 ;\nmin: +1 x1 ;\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotOPB, gotErr := ReadOPB(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadOPB(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadOPB(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantOPB, gotOPB); diff != "" {
+				t.Errorf("ReadOPB(): instance mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadOPB_longLine(t *testing.T) {
+	const nVars = 10000
+	sb := strings.Builder{}
+	want := OPBConstraint{Relation: OPBGreaterOrEqual, RHS: 1}
+	for v := 1; v <= nVars; v++ {
+		fmt.Fprintf(&sb, "+1 x%d ", v)
+		want.Terms = append(want.Terms, OPBTerm{Coef: 1, Lit: v})
+	}
+	sb.WriteString(">= 1 ;\n")
+
+	got, err := ReadOPB(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatalf("ReadOPB(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff([]OPBConstraint{want}, got.Constraints); diff != "" {
+		t.Errorf("ReadOPB(): constraints mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadOPB_header(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		input string
+	}{
+		{
+			desc:  "first line",
+			input: "* #variable= 5 #constraint= 1\n+1 x1 >= 1 ;\n",
+		},
+		{
+			desc:  "after empty lines",
+			input: "\n  \n* #variable= 5 #constraint= 1\n+1 x1 >= 1 ;\n",
+		},
+		{
+			desc:  "byte order mark",
+			input: "\ufeff* #variable= 5 #constraint= 1\n+1 x1 >= 1 ;\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := ReadOPB(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("ReadOPB(): want no error, got %s", err)
+			}
+
+			if got.NumVars != 5 || got.NumConstraints != 1 {
+				t.Errorf("ReadOPB(): want 5 variables and 1 constraint, got %d and %d", got.NumVars, got.NumConstraints)
+			}
+		})
+	}
+}
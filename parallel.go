@@ -0,0 +1,139 @@
+package dimacs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// minParallelChunk is the minimum number of bytes parsed by each worker of
+// ReadCNFParallel. Smaller inputs are parsed with fewer workers.
+var minParallelChunk = 1 << 20
+
+// ReadCNFParallel is like ReadCNF but splits the input into line-aligned
+// chunks whose clauses are parsed concurrently by the given number of workers
+// (runtime.GOMAXPROCS(0) if workers is not positive). The clauses are returned
+// in the order in which they appear in the input.
+//
+// The whole input is loaded in memory before being parsed. Inputs that are not
+// well-formed (as well as the rare inputs that use the "%" end marker) are
+// parsed again sequentially so that the returned formula and error are always
+// the same as the ones returned by ReadCNF.
+func ReadCNFParallel(r io.Reader, workers int) (CNFFormula, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return CNFFormula{}, err
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if n := len(data) / minParallelChunk; n < workers {
+		workers = n
+	}
+	if workers <= 1 || bytes.IndexByte(data, '%') >= 0 {
+		return ReadCNFBytes(data)
+	}
+
+	chunks := splitLines(data, workers)
+	results := make([]chunkBuilder, len(chunks))
+	wg := sync.WaitGroup{}
+	for i := range chunks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rd := Reader{}
+			rd.resetBytes(chunks[i])
+			if _, err := rd.read(context.Background(), &results[i], ReadOptions{}); err != nil {
+				results[i].failed = true
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if f, ok := mergeChunks(chunks, results); ok {
+		return f, nil
+	}
+	return ReadCNFBytes(data) // reports the same error as ReadCNF
+}
+
+// splitLines splits data into at most n chunks of similar size that end with a
+// complete line.
+func splitLines(data []byte, n int) [][]byte {
+	chunks := make([][]byte, 0, n)
+	size := len(data) / n
+	for len(data) > 0 {
+		end := len(data)
+		if len(chunks) < n-1 && size < len(data) {
+			end = size
+			if i := bytes.IndexByte(data[end:], '\n'); i >= 0 {
+				end += i + 1
+			} else {
+				end = len(data)
+			}
+		}
+		chunks = append(chunks, data[:end])
+		data = data[end:]
+	}
+	return chunks
+}
+
+// mergeChunks assembles the formula from the clauses and problem lines parsed
+// in each chunk. It returns false if the input is not a well-formed formula.
+func mergeChunks(chunks [][]byte, results []chunkBuilder) (CNFFormula, bool) {
+	b := cnfBuilder{}
+	nClauses := 0
+	for i, res := range results {
+		if res.failed || len(res.problems) > 1 {
+			return CNFFormula{}, false
+		}
+		if i > 0 && bytes.HasPrefix(chunks[i], []byte(utf8BOM)) {
+			return CNFFormula{}, false // only allowed on the first line
+		}
+		if len(res.problems) == 1 {
+			p := res.problems[0]
+			if nClauses+p.nPrevious != 0 || b.Problem(p.problem, p.nVars, p.nClauses) != nil {
+				return CNFFormula{}, false
+			}
+		}
+		nClauses += len(res.clauses)
+	}
+	if b.cnf == nil || nClauses != b.nClauses {
+		return CNFFormula{}, false
+	}
+	for _, res := range results {
+		b.cnf.Clauses = append(b.cnf.Clauses, res.clauses...)
+	}
+	return *b.cnf, true
+}
+
+// chunkBuilder collects the clauses and problem lines of a chunk without
+// validating them.
+type chunkBuilder struct {
+	clauses  [][]int
+	problems []chunkProblem
+	failed   bool
+}
+
+// chunkProblem is a problem line found in a chunk.
+type chunkProblem struct {
+	problem   string
+	nVars     int
+	nClauses  int
+	nPrevious int // number of clauses before the problem line in the chunk
+}
+
+func (b *chunkBuilder) Problem(p string, v int, c int) error {
+	b.problems = append(b.problems, chunkProblem{p, v, c, len(b.clauses)})
+	return nil
+}
+
+func (b *chunkBuilder) Clause(tmp []int) error {
+	c := make([]int, len(tmp))
+	copy(c, tmp)
+	b.clauses = append(b.clauses, c)
+	return nil
+}
+
+func (b *chunkBuilder) Comment(c string) error { return nil } // ignore comments
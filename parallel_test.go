@@ -0,0 +1,81 @@
+package dimacs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadCNFParallel(t *testing.T) {
+	defer func(n int) { minParallelChunk = n }(minParallelChunk)
+	minParallelChunk = 8 // parse even small inputs in parallel
+
+	inputs := []string{
+		"",
+		"c no problem or clause",
+		"p cnf 3 a\n1 2 0\n1 2 0\n1 2 0\n",
+		"c comment\np cnf 3 4\n1 2 3 0\n-1 0\np cnf 3 4\n1 2 0\n2 0\n",
+		"1 2 3 0\n1 2 3 0\n1 2 3 0\np cnf 3 4\n1 2 0\n",
+		"p cnf 3 1\n1 2 3 0\n2 3 0\n1 2 3 0\n1 2 3 0\n",
+		"p cnf 3 5\n1 2 3 0\n1 2 3 0\n1 2 3 0\n1 2 3 0\n",
+		"p cnf 3 3\n1 2 3 0\n1 2 3 0\n1 a 3 0\n",
+		"p cnf 3 3\n1 2 3 0\n1 2 3 0\n\ufeff1 2 3 0\n",
+		"p cnf 3 3\n1 2 3 0\n1 2 3 0 c trailing comment\n1 2 3 0\n",
+		validCNF_noComments,
+		validCNF_manyComments,
+		validCNF_endOfFile,
+		validCNF_crlf,
+		validCNF_bom,
+		randomCNF(100, 400, 3),
+	}
+
+	for _, workers := range []int{2, 3, 8} {
+		for _, input := range inputs {
+			wantCNF, wantErr := ReadCNF(strings.NewReader(input))
+
+			gotCNF, gotErr := ReadCNFParallel(strings.NewReader(input), workers)
+
+			if !errorEqual(gotErr, wantErr) {
+				t.Errorf("ReadCNFParallel(%.20q, %d): want error %v, got %v", input, workers, wantErr, gotErr)
+			}
+			var wantPE, gotPE *ParseError
+			if errors.As(wantErr, &wantPE) && errors.As(gotErr, &gotPE) && wantPE.Line != gotPE.Line {
+				t.Errorf("ReadCNFParallel(%.20q, %d): want error at line %d, got %d", input, workers, wantPE.Line, gotPE.Line)
+			}
+			if diff := cmp.Diff(wantCNF, gotCNF); diff != "" {
+				t.Errorf("ReadCNFParallel(%.20q, %d): CNF mismatch (-want +got):\n%s", input, workers, diff)
+			}
+		}
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	data := []byte("a\nbb\nccc\ndddd\ne")
+
+	got := splitLines(data, 3)
+
+	want := [][]byte{[]byte("a\nbb\nccc\n"), []byte("dddd\ne")}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("splitLines(): chunks mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// BenchmarkReadCNFParallel_large is meant to be compared with
+// BenchmarkReadCNF_large. Workers beyond GOMAXPROCS do not provide any speedup.
+func BenchmarkReadCNFParallel_large(b *testing.B) {
+	input := randomCNF(100000, 400000, 3)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.SetBytes(int64(len(input)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := ReadCNFParallel(strings.NewReader(input), workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
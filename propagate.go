@@ -0,0 +1,99 @@
+package dimacs
+
+// UnitClauses returns the literal of each unit clause (i.e. clause of length
+// one) of the formula, in order of appearance.
+func (f CNFFormula) UnitClauses() []int {
+	units := []int{}
+	for _, c := range f.Clauses {
+		if len(c) == 1 {
+			units = append(units, c[0])
+		}
+	}
+	return units
+}
+
+// PropagateUnits applies unit propagation to the formula until fixpoint: the
+// literal of each unit clause is assigned to true, the clauses it satisfies are
+// removed, and its negation is removed from the remaining clauses, which can
+// in turn become unit. Clauses are simplified in place and NumVars is left
+// unchanged.
+//
+// PropagateUnits returns the forced literals in the order they were assigned.
+// If an empty clause is derived (or was already present), conflict is true and
+// the formula is reduced to a single empty clause.
+func (f *CNFFormula) PropagateUnits() (assignments []int, conflict bool) {
+	numVars := f.NumVars
+	if v := maxVar(f.Clauses); v > numVars {
+		numVars = v
+	}
+	values := make([]int8, numVars+1) // 1 (true), -1 (false), or 0 (unassigned)
+	value := func(l int) int8 {
+		if l < 0 {
+			return -values[-l]
+		}
+		return values[l]
+	}
+
+	assignments = []int{}
+	for changed := true; changed; {
+		changed = false
+		kept := f.Clauses[:0]
+		for _, c := range f.Clauses {
+			satisfied := false
+			simplified := c[:0]
+			for _, l := range c {
+				switch value(l) {
+				case 1:
+					satisfied = true
+				case 0:
+					simplified = append(simplified, l)
+				}
+			}
+			switch {
+			case satisfied:
+				continue
+			case len(simplified) == 0:
+				f.Clauses = [][]int{{}}
+				return assignments, true
+			case isUnit(simplified):
+				l := simplified[0]
+				if l < 0 {
+					values[-l] = -1
+				} else {
+					values[l] = 1
+				}
+				assignments = append(assignments, l)
+				changed = true
+				continue // the unit clause is now satisfied
+			}
+			kept = append(kept, simplified)
+		}
+		for i := len(kept); i < len(f.Clauses); i++ {
+			f.Clauses[i] = nil // allow removed clauses to be garbage collected
+		}
+		f.Clauses = kept
+	}
+	return assignments, false
+}
+
+// isUnit returns true if c is non-empty and all its literals are identical.
+func isUnit(c []int) bool {
+	if len(c) == 0 {
+		return false
+	}
+	for _, l := range c[1:] {
+		if l != c[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// Propagate is like PropagateUnits but returns the simplified formula instead
+// of modifying f. It also returns the forced literals and false if a conflict
+// was derived (in which case the simplified formula has a single empty clause).
+func (f CNFFormula) Propagate() (CNFFormula, []int, bool) {
+	g := f.Clone()
+	assignments, conflict := g.PropagateUnits()
+	return g, assignments, !conflict
+}
@@ -0,0 +1,150 @@
+package dimacs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnitClauses(t *testing.T) {
+	f := CNFFormula{
+		NumVars: 3,
+		Clauses: [][]int{{1, 2}, {-3}, {}, {2}, {-3}},
+	}
+
+	got := f.UnitClauses()
+
+	if diff := cmp.Diff([]int{-3, 2, -3}, got); diff != "" {
+		t.Errorf("UnitClauses(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPropagateUnits(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		clauses         [][]int
+		wantClauses     [][]int
+		wantAssignments []int
+		wantConflict    bool
+	}{
+		{
+			desc:            "no unit clauses",
+			clauses:         [][]int{{1, 2}, {-1, -2}},
+			wantClauses:     [][]int{{1, 2}, {-1, -2}},
+			wantAssignments: []int{},
+			wantConflict:    false,
+		},
+		{
+			desc:            "chained propagation",
+			clauses:         [][]int{{1, 2, 3}, {-1, 2}, {1}, {-2, -3, 4}, {-4, -3}},
+			wantClauses:     [][]int{{-3, 4}, {-4, -3}},
+			wantAssignments: []int{1, 2},
+			wantConflict:    false,
+		},
+		{
+			desc:            "propagation to fixpoint",
+			clauses:         [][]int{{-3, 4}, {-2, 3}, {-1, 2}, {1}},
+			wantClauses:     [][]int{},
+			wantAssignments: []int{1, 2, 3, 4},
+			wantConflict:    false,
+		},
+		{
+			desc:            "repeated literals",
+			clauses:         [][]int{{2, 2}, {-2, 3, -1}, {1}},
+			wantClauses:     [][]int{},
+			wantAssignments: []int{2, 1, 3},
+			wantConflict:    false,
+		},
+		{
+			desc:            "conflict",
+			clauses:         [][]int{{1}, {-1, 2}, {-2, -1}, {3, 4}},
+			wantClauses:     [][]int{{}},
+			wantAssignments: []int{1, 2},
+			wantConflict:    true,
+		},
+		{
+			desc:            "empty clause",
+			clauses:         [][]int{{1, 2}, {}},
+			wantClauses:     [][]int{{}},
+			wantAssignments: []int{},
+			wantConflict:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := CNFFormula{NumVars: 4, Clauses: tc.clauses}
+
+			gotAssignments, gotConflict := f.PropagateUnits()
+
+			if gotConflict != tc.wantConflict {
+				t.Errorf("PropagateUnits(): want conflict %t, got %t", tc.wantConflict, gotConflict)
+			}
+			if diff := cmp.Diff(tc.wantAssignments, gotAssignments); diff != "" {
+				t.Errorf("PropagateUnits(): assignments mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantClauses, f.Clauses); diff != "" {
+				t.Errorf("PropagateUnits(): clauses mismatch (-want +got):\n%s", diff)
+			}
+			if f.NumVars != 4 {
+				t.Errorf("PropagateUnits(): want NumVars 4, got %d", f.NumVars)
+			}
+		})
+	}
+}
+
+func TestPropagate(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		clauses         [][]int
+		want            CNFFormula
+		wantAssignments []int
+		wantOK          bool
+	}{
+		{
+			desc:    "simplified formula",
+			clauses: [][]int{{1, 2, 3}, {-1, 2}, {1}, {-2, -3, 4}, {-4, -3}},
+			want: CNFFormula{
+				NumVars: 4,
+				Clauses: [][]int{{-3, 4}, {-4, -3}},
+			},
+			wantAssignments: []int{1, 2},
+			wantOK:          true,
+		},
+		{
+			desc:    "conflict",
+			clauses: [][]int{{2}, {-1, -2}, {1, -2}},
+			want: CNFFormula{
+				NumVars: 4,
+				Clauses: [][]int{{}},
+			},
+			wantAssignments: []int{2, -1},
+			wantOK:          false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := CNFFormula{NumVars: 4, Clauses: tc.clauses}
+			original := CNFFormula{NumVars: 4, Clauses: [][]int{}}
+			for _, c := range tc.clauses {
+				original.Clauses = append(original.Clauses, append([]int{}, c...))
+			}
+
+			got, gotAssignments, gotOK := f.Propagate()
+
+			if gotOK != tc.wantOK {
+				t.Errorf("Propagate(): want ok %t, got %t", tc.wantOK, gotOK)
+			}
+			if diff := cmp.Diff(tc.wantAssignments, gotAssignments); diff != "" {
+				t.Errorf("Propagate(): assignments mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Propagate(): CNF mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(original, f); diff != "" {
+				t.Errorf("Propagate(): receiver modified (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
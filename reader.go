@@ -0,0 +1,62 @@
+package dimacs
+
+import (
+	"bufio"
+	"context"
+	"io"
+)
+
+// Reader reads DIMACS files and populates builders. Unlike ReadBuilder, which
+// allocates fresh buffers on every call, a Reader keeps its line and clause
+// buffers across calls to Reset, which reduces the allocations when parsing
+// many (small) files in sequence.
+//
+// A Reader is not safe for concurrent use.
+type Reader struct {
+	br        *bufio.Reader
+	lineBuf   []byte // holds the lines that do not fit in br's buffer
+	clauseBuf []int
+
+	// fromBytes is true if lines are read from data rather than br.
+	fromBytes bool
+	data      []byte
+}
+
+// readBufferSize is the size of the buffer used to read the input. Lines longer
+// than the buffer are still supported.
+const readBufferSize = 4096
+
+// NewReader returns a new Reader reading from r.
+func NewReader(r io.Reader) *Reader {
+	rd := &Reader{}
+	rd.Reset(r)
+	return rd
+}
+
+// Reset discards any state and switches the Reader to read from r while
+// retaining its buffers.
+func (rd *Reader) Reset(r io.Reader) {
+	if rd.br == nil {
+		// Not created with NewReaderSize(r, ...) which would return r itself
+		// if it already is a large enough *bufio.Reader.
+		rd.br = bufio.NewReaderSize(nil, readBufferSize)
+	}
+	rd.br.Reset(r)
+	rd.fromBytes, rd.data = false, nil
+}
+
+// resetBytes is like Reset but switches the Reader to read the lines of data
+// directly from the slice.
+func (rd *Reader) resetBytes(data []byte) {
+	if rd.br != nil {
+		rd.br.Reset(nil)
+	}
+	rd.fromBytes, rd.data = true, data
+}
+
+// ReadInto reads the DIMACS file from the underlying reader and populates b as
+// ReadBuilder does.
+func (rd *Reader) ReadInto(b Builder) error {
+	_, err := rd.read(context.Background(), b, ReadOptions{})
+	return err
+}
@@ -0,0 +1,68 @@
+package dimacs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReader_reset(t *testing.T) {
+	inputs := []string{validCNF_noComments, validCNF_manyComments, validCNF_endOfFile}
+	rd := NewReader(nil)
+
+	for _, input := range inputs {
+		rd.Reset(strings.NewReader(input))
+		b := &CollectBuilder{}
+
+		if err := rd.ReadInto(b); err != nil {
+			t.Fatalf("ReadInto(): want no error, got %s", err)
+		}
+
+		got, err := b.Formula()
+		if err != nil {
+			t.Fatalf("Formula(): want no error, got %s", err)
+		}
+		want, _ := ReadCNF(strings.NewReader(input))
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ReadInto(): CNF mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestReader_errors(t *testing.T) {
+	rd := NewReader(strings.NewReader("p cnf 3 1\n1 x 0\n"))
+
+	if err := rd.ReadInto(&testBuilder{}); err == nil {
+		t.Errorf("ReadInto(): want error, got nil")
+	}
+
+	rd.Reset(strings.NewReader(validCNF_noComments))
+
+	if err := rd.ReadInto(&testBuilder{}); err != nil {
+		t.Errorf("ReadInto(): want no error after Reset, got %s", err)
+	}
+}
+
+func BenchmarkReadBuilder_small(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := ReadBuilder(strings.NewReader(validCNF_manyComments), &testBuilder{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReader_small(b *testing.B) {
+	b.ReportAllocs()
+	rd := NewReader(nil)
+	sr := strings.NewReader(validCNF_manyComments)
+	tb := &testBuilder{}
+	for i := 0; i < b.N; i++ {
+		sr.Reset(validCNF_manyComments)
+		rd.Reset(sr)
+		if err := rd.ReadInto(tb); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package dimacs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SATFormula represents a formula in the DIMACS SAT format ("p sat"), in which
+// a single arbitrarily nested Boolean expression is given over variables 1 to
+// NumVars (inclusive).
+type SATFormula struct {
+	NumVars int
+	Expr    Expr
+}
+
+// Expr is a node of a Boolean expression tree. Its concrete type is one of
+// Lit, Not, And, Or or Xor.
+type Expr interface {
+	isExpr()
+}
+
+// Lit is a literal expression: i for variable i and -i for its negation.
+type Lit int
+
+// Not is the negation of an expression.
+type Not struct {
+	X Expr
+}
+
+// And is the conjunction of expressions. The empty conjunction is true.
+type And []Expr
+
+// Or is the disjunction of expressions. The empty disjunction is false.
+type Or []Expr
+
+// Xor is the exclusive disjunction of expressions, that is true if an odd
+// number of them is true. The empty exclusive disjunction is false.
+type Xor []Expr
+
+func (Lit) isExpr() {}
+func (Not) isExpr() {}
+func (And) isExpr() {}
+func (Or) isExpr()  {}
+func (Xor) isExpr() {}
+
+// ReadSAT parses and returns a formula in the DIMACS SAT format from the given
+// reader. The problem line is "p sat <vars>" or "p satx <vars>" and is followed
+// by a single expression, possibly spread over several lines, defined as:
+//
+//	<expr> ::= <lit> | "(" <expr> ")" | "-" <expr>
+//	         | "*(" <expr>* ")" | "+(" <expr>* ")" | "xor(" <expr>* ")"
+//
+// where "*" is a conjunction, "+" a disjunction and <lit> a non-zero integer
+// as in CNF files (e.g. "-3"). Exclusive disjunctions are only allowed in
+// "satx" files. The "sate" and "satex" variants, which add equivalences, are
+// not supported. Comment lines (starting with "c") are ignored.
+func ReadSAT(r io.Reader) (SATFormula, error) {
+	rd := Reader{}
+	rd.Reset(r)
+	p := satParser{}
+	hasProblem := false
+
+	n := 0
+	for {
+		line, err := rd.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return SATFormula{}, err
+		}
+		n++
+		if n == 1 {
+			line = bytes.TrimPrefix(line, []byte(utf8BOM))
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == 'c' {
+			continue
+		}
+
+		text := string(line)
+		if line[0] == 'p' {
+			if hasProblem {
+				return SATFormula{}, newParseError(n, text, ErrDuplicateProblem)
+			}
+			if err := p.parseProblem(text); err != nil {
+				return SATFormula{}, newParseError(n, text, err)
+			}
+			hasProblem = true
+			continue
+		}
+		if !hasProblem {
+			err := fmt.Errorf("%w: %q", ErrClauseBeforeProblem, text)
+			return SATFormula{}, newParseError(n, text, err)
+		}
+		if err := p.tokenize(text, n); err != nil {
+			return SATFormula{}, newParseError(n, text, err)
+		}
+	}
+	if !hasProblem {
+		return SATFormula{}, newParseError(n, "", ErrNoProblemLine)
+	}
+
+	p.lastLine = n
+	expr, err := p.parseExpr()
+	if err != nil {
+		return SATFormula{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return SATFormula{}, p.tokenError(p.tokens[p.pos], fmt.Errorf("unexpected %q after the formula", p.tokens[p.pos].text))
+	}
+	return SATFormula{NumVars: p.nVars, Expr: expr}, nil
+}
+
+// satToken is a token of a SAT formula along with the line it comes from.
+type satToken struct {
+	text     string
+	line     int
+	lineText string
+}
+
+// satParser is a recursive descent parser for the expressions of SAT files.
+type satParser struct {
+	nVars    int
+	xor      bool // whether xor expressions are allowed
+	tokens   []satToken
+	pos      int
+	lastLine int
+}
+
+func (p *satParser) parseProblem(line string) error {
+	parts := strings.Fields(line)
+	if len(parts) != 3 {
+		return fmt.Errorf("%w: should have 3 parts, got %d: %s", ErrInvalidProblemLine, len(parts), line)
+	}
+	switch parts[1] {
+	case "sat":
+	case "satx":
+		p.xor = true
+	default:
+		return fmt.Errorf("%w: expected \"sat\" or \"satx\" problem, got %q", ErrInvalidProblemType, parts[1])
+	}
+	nVars, err := strconv.Atoi(parts[2])
+	if err != nil || nVars < 0 {
+		return fmt.Errorf("%w: invalid number of variables %q", ErrInvalidProblemLine, parts[2])
+	}
+	p.nVars = nVars
+	return nil
+}
+
+// tokenize splits the given line into tokens and appends them to p.tokens. A
+// "-" immediately followed by digits is a negative literal, otherwise it is a
+// negation.
+func (p *satParser) tokenize(line string, n int) error {
+	for i := 0; i < len(line); {
+		c := line[i]
+		j := i + 1
+		switch {
+		case isSpace(c):
+			i++
+			continue
+		case c == '(' || c == ')' || c == '*' || c == '+':
+		case c == '-' || isDigit(c):
+			for j < len(line) && isDigit(line[j]) {
+				j++
+			}
+		case 'a' <= c && c <= 'z':
+			for j < len(line) && 'a' <= line[j] && line[j] <= 'z' {
+				j++
+			}
+		default:
+			return fmt.Errorf("unexpected character %q", c)
+		}
+		p.tokens = append(p.tokens, satToken{text: line[i:j], line: n, lineText: line})
+		i = j
+	}
+	return nil
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// parseExpr parses the expression starting at the current token.
+func (p *satParser) parseExpr() (Expr, error) {
+	if p.pos == len(p.tokens) {
+		return nil, p.eofError()
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+
+	switch tok.text {
+	case "(":
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	case "-":
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return Not{X: x}, nil
+	case "*", "+", "xor":
+		if tok.text == "xor" && !p.xor {
+			return nil, p.tokenError(tok, fmt.Errorf("xor is only allowed in \"satx\" files"))
+		}
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		xs := []Expr{}
+		for p.pos == len(p.tokens) || p.tokens[p.pos].text != ")" {
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			xs = append(xs, x)
+		}
+		p.pos++ // skip ")"
+		switch tok.text {
+		case "*":
+			return And(xs), nil
+		case "+":
+			return Or(xs), nil
+		default:
+			return Xor(xs), nil
+		}
+	}
+
+	l, err := strconv.Atoi(tok.text)
+	if err != nil {
+		return nil, p.tokenError(tok, fmt.Errorf("unexpected %q", tok.text))
+	}
+	if l == 0 {
+		return nil, p.tokenError(tok, fmt.Errorf("invalid literal 0"))
+	}
+	if abs(l) > p.nVars {
+		err := fmt.Errorf("%w: variable %d, expected at most %d", ErrVarOutOfRange, abs(l), p.nVars)
+		return nil, newParseError(tok.line, tok.lineText, err)
+	}
+	return Lit(l), nil
+}
+
+// expect consumes the current token if it is want and returns an error
+// otherwise.
+func (p *satParser) expect(want string) error {
+	if p.pos == len(p.tokens) {
+		return p.eofError()
+	}
+	tok := p.tokens[p.pos]
+	if tok.text != want {
+		return p.tokenError(tok, fmt.Errorf("expected %q, got %q", want, tok.text))
+	}
+	p.pos++
+	return nil
+}
+
+func (p *satParser) tokenError(tok satToken, err error) *ParseError {
+	return newParseError(tok.line, tok.lineText, err)
+}
+
+func (p *satParser) eofError() *ParseError {
+	err := fmt.Errorf("unexpected end of formula")
+	return newParseError(p.lastLine, "", err)
+}
@@ -0,0 +1,215 @@
+package dimacs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const validSAT = `c example from the DIMACS specification
+p sat 4
+(*(+(1 3 -4)
+   +(4)
+   +(2 3)))
+`
+
+func TestReadSAT(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		want    SATFormula
+		wantErr bool
+	}{
+		{
+			desc:  "valid formula",
+			input: validSAT,
+			want: SATFormula{
+				NumVars: 4,
+				Expr:    And{Or{Lit(1), Lit(3), Lit(-4)}, Or{Lit(4)}, Or{Lit(2), Lit(3)}},
+			},
+		},
+		{
+			desc:  "single literal",
+			input: "p sat 1\n-1\n",
+			want:  SATFormula{NumVars: 1, Expr: Lit(-1)},
+		},
+		{
+			desc:  "negation",
+			input: "p sat 2\n- (+(1 -2))",
+			want:  SATFormula{NumVars: 2, Expr: Not{Or{Lit(1), Lit(-2)}}},
+		},
+		{
+			desc:  "nested negations",
+			input: "p sat 2\n-(-(*(1 -2)))\n",
+			want:  SATFormula{NumVars: 2, Expr: Not{Not{And{Lit(1), Lit(-2)}}}},
+		},
+		{
+			desc:  "empty conjunction and disjunction",
+			input: "p sat 0\n*(+() *())\n",
+			want:  SATFormula{NumVars: 0, Expr: And{Or{}, And{}}},
+		},
+		{
+			desc:  "xor",
+			input: "c comment\np satx 3\nc comment\n*(xor(1 2 -3)\n-xor(1))\n",
+			want:  SATFormula{NumVars: 3, Expr: And{Xor{Lit(1), Lit(2), Lit(-3)}, Not{Xor{Lit(1)}}}},
+		},
+		{
+			desc:    "xor in sat file",
+			input:   "p sat 2\nxor(1 2)\n",
+			wantErr: true,
+		},
+		{
+			desc:    "missing problem line",
+			input:   "c comment\n",
+			wantErr: true,
+		},
+		{
+			desc:    "formula before problem line",
+			input:   "+(1)\np sat 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "duplicate problem line",
+			input:   "p sat 1\np sat 1\n1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid problem type",
+			input:   "p cnf 1 1\n1 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "unsupported equivalences",
+			input:   "p sate 1\n=(1 1)\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid number of variables",
+			input:   "p sat x\n1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "missing formula",
+			input:   "p sat 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "unbalanced parentheses",
+			input:   "p sat 2\n*(+(1 2)\n",
+			wantErr: true,
+		},
+		{
+			desc:    "missing opening parenthesis",
+			input:   "p sat 2\n*1 2)\n",
+			wantErr: true,
+		},
+		{
+			desc:  "largest variable",
+			input: "p sat 3\n(+(-3 (3)))\n",
+			want:  SATFormula{NumVars: 3, Expr: Or{Lit(-3), Lit(3)}},
+		},
+		{
+			desc:    "extra closing parenthesis",
+			input:   "p sat 2\n+(1 2))\n",
+			wantErr: true,
+		},
+		{
+			desc:    "unclosed parenthesis",
+			input:   "p sat 2\n(1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "negative variable out of range",
+			input:   "p sat 2\n*(1 -3)\n",
+			wantErr: true,
+		},
+		{
+			desc:    "unexpected closing parenthesis",
+			input:   "p sat 2\n)\n",
+			wantErr: true,
+		},
+		{
+			desc:    "trailing tokens",
+			input:   "p sat 2\n+(1 2)\n-1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "zero literal",
+			input:   "p sat 2\n+(1 0)\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid character",
+			input:   "p sat 2\n+(1 & 2)\n",
+			wantErr: true,
+		},
+		{
+			desc:    "unknown operator",
+			input:   "p sat 2\nand(1 2)\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotErr := ReadSAT(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadSAT(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadSAT(): want no error, got %s", gotErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ReadSAT(): formula mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadSAT_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		input    string
+		wantLine int
+		wantErr  error
+	}{
+		{
+			desc:     "variable out of range",
+			input:    "p sat 2\n*(1\n+(-3 2))\n",
+			wantLine: 3,
+			wantErr:  ErrVarOutOfRange,
+		},
+		{
+			desc:     "invalid problem type",
+			input:    "c comment\np sat3 2\n1\n",
+			wantLine: 2,
+			wantErr:  ErrInvalidProblemType,
+		},
+		{
+			desc:     "no problem line",
+			input:    "c comment\n\n",
+			wantLine: 2,
+			wantErr:  ErrNoProblemLine,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, gotErr := ReadSAT(strings.NewReader(tc.input))
+
+			var pe *ParseError
+			if !errors.As(gotErr, &pe) || !errors.Is(gotErr, tc.wantErr) {
+				t.Fatalf("ReadSAT(): want error %q, got %v", tc.wantErr, gotErr)
+			}
+			if pe.Line != tc.wantLine {
+				t.Errorf("ReadSAT(): want error on line %d, got %d", tc.wantLine, pe.Line)
+			}
+		})
+	}
+}
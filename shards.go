@@ -0,0 +1,56 @@
+package dimacs
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// ReadCNFMulti is like ReadCNF but reads a single formula whose content is
+// spread across the given readers, read in order (e.g. a header and several
+// shards of clauses). The readers are concatenated as with io.MultiReader: a
+// line, and thus a clause, can start in one reader and continue in the next.
+// A space is inserted between two readers if the first ends with a token and
+// the second starts with one, so that tokens are never merged at the edge
+// (e.g. "1 -2" followed by "3 0" is read as the clause "1 -2 3 0").
+func ReadCNFMulti(readers ...io.Reader) (CNFFormula, error) {
+	return ReadCNF(&multiReader{readers: readers})
+}
+
+// multiReader is the concatenation of several readers, a space being inserted
+// between two readers that would otherwise merge a token.
+type multiReader struct {
+	readers []io.Reader // readers that remain to be read
+	cur     io.Reader   // reader being read, nil if none
+	last    byte        // last byte returned, 0 if none
+}
+
+func (mr *multiReader) Read(p []byte) (int, error) {
+	for {
+		if mr.cur == nil {
+			if len(mr.readers) == 0 {
+				return 0, io.EOF
+			}
+			br := bufio.NewReader(mr.readers[0])
+			mr.readers = mr.readers[1:]
+			mr.cur = br
+			if mr.last != 0 && !isSpace(mr.last) {
+				if b, err := br.Peek(1); err == nil && !isSpace(b[0]) {
+					mr.cur = io.MultiReader(strings.NewReader(" "), br)
+				}
+			}
+		}
+		n, err := mr.cur.Read(p)
+		if n > 0 {
+			mr.last = p[n-1]
+		}
+		if err == io.EOF {
+			mr.cur = nil
+			if n == 0 {
+				continue
+			}
+			err = nil // io.EOF is returned once all the readers are read
+		}
+		return n, err
+	}
+}
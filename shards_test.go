@@ -0,0 +1,127 @@
+package dimacs
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadCNFMulti(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		shards  []string
+		wantCNF CNFFormula
+		wantErr bool
+	}{
+		{
+			desc:    "single reader",
+			shards:  []string{testFormulaDIMACS},
+			wantCNF: testFormula,
+			wantErr: false,
+		},
+		{
+			desc:    "header and clauses",
+			shards:  []string{"c header\np cnf 3 4\n", "1 2 3 0\n1 -2 3 0\n", "1 -3 0\n-2 -3 0\n"},
+			wantCNF: testFormula,
+			wantErr: false,
+		},
+		{
+			desc:    "line split across shards",
+			shards:  []string{"p cnf 3 4\n1 2 3 0\n1 -2 ", "3 0\n1 -3 0\n-2 -3 0\n"},
+			wantCNF: testFormula,
+			wantErr: false,
+		},
+		{
+			desc:    "token at the end of a shard",
+			shards:  []string{"p cnf 3 1\n1 -2", "3 0\n"},
+			wantCNF: CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2, 3}}},
+			wantErr: false,
+		},
+		{
+			desc:    "token at the end of a shard before empty shards",
+			shards:  []string{"p cnf 3 1\n1", "", "-2", "", "3 0"},
+			wantCNF: CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2, 3}}},
+			wantErr: false,
+		},
+		{
+			desc:    "empty shards",
+			shards:  []string{"", "p cnf 3 4\n1 2 3 0\n", "", "1 -2 3 0\n1 -3 0\n-2 -3 0", ""},
+			wantCNF: testFormula,
+			wantErr: false,
+		},
+		{
+			desc:    "CRLF line endings",
+			shards:  []string{"p cnf 3 4\r\n1 2 3 0\r", "\n1 -2 3 0\r\n1 -3 0\r\n-2 -3 0\r\n"},
+			wantCNF: testFormula,
+			wantErr: false,
+		},
+		{
+			desc:    "no readers",
+			shards:  nil,
+			wantCNF: CNFFormula{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			readers := make([]io.Reader, len(tc.shards))
+			for i, s := range tc.shards {
+				readers[i] = iotest.OneByteReader(strings.NewReader(s))
+			}
+
+			gotCNF, gotErr := ReadCNFMulti(readers...)
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadCNFMulti(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadCNFMulti(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantCNF, gotCNF); diff != "" {
+				t.Errorf("ReadCNFMulti(): CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMultiReader(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		shards []string
+		want   string
+	}{
+		{
+			desc:   "no readers",
+			shards: nil,
+			want:   "",
+		},
+		{
+			desc:   "separated tokens",
+			shards: []string{"1 2\n", "3", " 4", "\n"},
+			want:   "1 2\n3 4\n",
+		},
+		{
+			desc:   "adjacent tokens",
+			shards: []string{"1 2", "", "3", "4\n"},
+			want:   "1 2 3 4\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			readers := make([]io.Reader, len(tc.shards))
+			for i, s := range tc.shards {
+				readers[i] = strings.NewReader(s)
+			}
+			r := &multiReader{readers: readers}
+
+			if err := iotest.TestReader(r, []byte(tc.want)); err != nil {
+				t.Errorf("multiReader: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,471 @@
+package dimacs
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// Dedup removes the clauses that are syntactically identical to a clause that
+// appears before them in the formula and returns the number of clauses that
+// were removed. Two clauses are considered identical if they contain the same
+// literals, regardless of their order (e.g. [1 -2] and [-2 1] are identical).
+// Repeated literals are significant: [1 1 -2] and [1 -2] are not identical
+// unless Normalize is first used to remove repeated literals.
+//
+// Dedup keeps the first occurrence of each clause and preserves the relative
+// order of the surviving clauses as well as the original order of the literals
+// within each of them.
+func (f *CNFFormula) Dedup() int {
+	seen := make(map[string]struct{}, len(f.Clauses))
+	var buf []int
+	kept := f.Clauses[:0]
+	for _, c := range f.Clauses {
+		var key string
+		key, buf = clauseKey(c, buf)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		kept = append(kept, c)
+	}
+	removed := len(f.Clauses) - len(kept)
+	for i := len(kept); i < len(f.Clauses); i++ {
+		f.Clauses[i] = nil // allow removed clauses to be garbage collected
+	}
+	f.Clauses = kept
+	return removed
+}
+
+// FindDuplicates returns the pairs of indices [i, j] such that clause j is
+// identical to clause i, in the same sense as Dedup, where i is the index of
+// the first occurrence of the clause and i < j. Pairs are returned in order of
+// increasing j. The formula is not modified.
+func (f CNFFormula) FindDuplicates() [][2]int {
+	first := make(map[string]int, len(f.Clauses))
+	var buf []int
+	var pairs [][2]int
+	for j, c := range f.Clauses {
+		var key string
+		key, buf = clauseKey(c, buf)
+		if i, ok := first[key]; ok {
+			pairs = append(pairs, [2]int{i, j})
+			continue
+		}
+		first[key] = j
+	}
+	return pairs
+}
+
+// DedupBuilder wraps a Builder and filters out the clauses that are identical
+// (regardless of the order of their literals) to a clause that was already
+// passed to the wrapped Builder. Problem and comment lines are forwarded
+// unchanged.
+//
+// Note that the number of clauses declared in the problem line is forwarded
+// as is. Wrapped builders that validate the number of clauses (e.g. the one
+// used by ReadCNF) will thus report missing clauses if duplicates are found.
+type DedupBuilder struct {
+	Builder
+
+	seen map[string]struct{}
+	buf  []int
+}
+
+// Clause forwards the clause to the wrapped Builder unless an identical clause
+// was already forwarded.
+func (b *DedupBuilder) Clause(tmpClause []int) error {
+	if b.seen == nil {
+		b.seen = map[string]struct{}{}
+	}
+	var key string
+	key, b.buf = clauseKey(tmpClause, b.buf)
+	if _, ok := b.seen[key]; ok {
+		return nil
+	}
+	b.seen[key] = struct{}{}
+	return b.Builder.Clause(tmpClause)
+}
+
+// clauseKey returns a string that uniquely identifies the set of literals in
+// clause c. The given buffer is used to sort the literals without modifying c
+// and is returned so that it can be reused in subsequent calls.
+func clauseKey(c []int, buf []int) (string, []int) {
+	buf = append(buf[:0], c...)
+	sort.Ints(buf)
+	key := make([]byte, 0, len(buf)*binary.MaxVarintLen32)
+	for _, l := range buf {
+		key = binary.AppendVarint(key, int64(l))
+	}
+	return string(key), buf
+}
+
+// RemoveTautologies removes the clauses that contain both a literal and its
+// negation (e.g. [1 -1 2]) and returns the number of clauses that were removed.
+// Tautological clauses are always satisfied and can thus be safely removed.
+// The relative order of the surviving clauses is preserved.
+func (f *CNFFormula) RemoveTautologies() int {
+	kept := f.Clauses[:0]
+	for _, c := range f.Clauses {
+		if !IsTautology(c) {
+			kept = append(kept, c)
+		}
+	}
+	removed := len(f.Clauses) - len(kept)
+	for i := len(kept); i < len(f.Clauses); i++ {
+		f.Clauses[i] = nil // allow removed clauses to be garbage collected
+	}
+	f.Clauses = kept
+	return removed
+}
+
+// RemoveSubsumed removes the clauses that are subsumed by another clause of the
+// formula and returns the number of clauses that were removed. Clause A
+// subsumes clause B if every literal of A also appears in B (e.g. [1 -2]
+// subsumes [3 -2 1]), in which case B can be removed without changing the
+// models of the formula. Of several identical clauses, only the first one is
+// kept. The relative order of the surviving clauses is preserved.
+//
+// Rather than comparing all pairs of clauses, each clause is only compared to
+// the clauses that contain its least frequent literal.
+func (f *CNFFormula) RemoveSubsumed() int {
+	// sets[i] holds the sorted unique literals of clause i.
+	sets := make([][]int, len(f.Clauses))
+	occs := map[int][]int{}
+	for i, c := range f.Clauses {
+		s := uniqueLiterals(append([]int(nil), c...))
+		sort.Ints(s)
+		sets[i] = s
+		for _, l := range s {
+			occs[l] = append(occs[l], i)
+		}
+	}
+
+	// Shorter clauses are processed first so that a clause is only used to
+	// remove other clauses if it is not itself subsumed.
+	order := make([]int, len(sets))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(sets[order[i]]) < len(sets[order[j]])
+	})
+
+	removed := make([]bool, len(sets))
+	for _, i := range order {
+		if removed[i] {
+			continue
+		}
+		a := sets[i]
+		if len(a) == 0 { // the empty clause subsumes every other clause
+			for j := range removed {
+				removed[j] = j != i
+			}
+			break
+		}
+		best := a[0]
+		for _, l := range a[1:] {
+			if len(occs[l]) < len(occs[best]) {
+				best = l
+			}
+		}
+		for _, j := range occs[best] {
+			if j != i && !removed[j] && isSubset(a, sets[j]) {
+				removed[j] = true
+			}
+		}
+	}
+
+	kept := f.Clauses[:0]
+	for i, c := range f.Clauses {
+		if !removed[i] {
+			kept = append(kept, c)
+		}
+	}
+	n := len(f.Clauses) - len(kept)
+	for i := len(kept); i < len(f.Clauses); i++ {
+		f.Clauses[i] = nil // allow removed clauses to be garbage collected
+	}
+	f.Clauses = kept
+	return n
+}
+
+// isSubset returns true if every element of a is also in b. Both slices must
+// be sorted in increasing order.
+func isSubset(a, b []int) bool {
+	if len(a) > len(b) {
+		return false
+	}
+	j := 0
+	for _, x := range a {
+		for j < len(b) && b[j] < x {
+			j++
+		}
+		if j == len(b) || b[j] != x {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// HasTautology returns true if at least one clause of the formula is a
+// tautology (see IsTautology).
+func (f CNFFormula) HasTautology() bool {
+	for _, c := range f.Clauses {
+		if IsTautology(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasEmptyClause returns true if the formula contains an empty clause, in
+// which case it is trivially unsatisfiable.
+func (f CNFFormula) HasEmptyClause() bool {
+	for _, c := range f.Clauses {
+		if len(c) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTriviallyUnsat returns true if the formula is unsatisfiable because it
+// contains an empty clause. A false result does not mean that the formula is
+// satisfiable.
+func (f CNFFormula) IsTriviallyUnsat() bool {
+	return f.HasEmptyClause()
+}
+
+// TautologyFilterBuilder wraps a Builder and filters out tautological clauses
+// (i.e. clauses that contain both a literal and its negation) so that they are
+// never passed to the wrapped Builder. Problem and comment lines are forwarded
+// unchanged.
+//
+// As with DedupBuilder, the number of clauses declared in the problem line is
+// forwarded as is.
+type TautologyFilterBuilder struct {
+	Builder
+}
+
+// Clause forwards the clause to the wrapped Builder unless it is tautological.
+func (b *TautologyFilterBuilder) Clause(tmpClause []int) error {
+	if IsTautology(tmpClause) {
+		return nil
+	}
+	return b.Builder.Clause(tmpClause)
+}
+
+// Compact renumbers the variables that appear in at least one clause to the
+// dense range 1..k, where k is the number of such variables, and sets NumVars
+// to k. Variables keep their relative order (i.e. the smallest used variable
+// becomes 1) and literals keep their sign. Clauses are rewritten in place.
+//
+// Compact returns the mapping from the old variables to the new ones, which
+// can be used to translate an assignment of the compacted formula back to the
+// original variables. It can be saved with WriteVarMap. Use Compacted to
+// leave the formula unchanged.
+func (f *CNFFormula) Compact() map[int]int {
+	vars := f.UsedVariables()
+	mapping := make(map[int]int, len(vars))
+	for i, v := range vars {
+		mapping[v] = i + 1
+	}
+	for _, c := range f.Clauses {
+		for i, l := range c {
+			if l < 0 {
+				c[i] = -mapping[-l]
+			} else {
+				c[i] = mapping[l]
+			}
+		}
+	}
+	f.NumVars = len(vars)
+	return mapping
+}
+
+// Compacted is like Compact but returns a compacted copy of the formula, which
+// is left unchanged, along with the mapping from the old variables to the new
+// ones.
+func (f CNFFormula) Compacted() (CNFFormula, map[int]int) {
+	g := f.Clone()
+	mapping := g.Compact()
+	return g, mapping
+}
+
+// UsedVariables returns the variables that appear in at least one clause of the
+// formula, in increasing order. Variables declared by NumVars but not used in
+// any clause are excluded.
+func (f CNFFormula) UsedVariables() []int {
+	used := map[int]struct{}{}
+	for _, c := range f.Clauses {
+		for _, l := range c {
+			used[abs(l)] = struct{}{}
+		}
+	}
+	vars := make([]int, 0, len(used))
+	for v := range used {
+		vars = append(vars, v)
+	}
+	sort.Ints(vars)
+	return vars
+}
+
+// NormalizeOptions controls which steps are applied by Normalize.
+type NormalizeOptions struct {
+	// SortLiterals sorts the literals within each clause by variable and then
+	// by sign, the negative literal coming first (e.g. [-1 1 -2 3]).
+	SortLiterals bool
+
+	// RemoveDuplicateLiterals removes repeated literals within each clause.
+	RemoveDuplicateLiterals bool
+
+	// RemoveTautologies removes clauses that contain both a literal and its
+	// negation (e.g. [1 -1 2]) as they are always satisfied.
+	RemoveTautologies bool
+
+	// RemoveDuplicateClauses removes clauses that are identical to a clause
+	// appearing before them in the formula (see Dedup).
+	RemoveDuplicateClauses bool
+}
+
+// Normalize applies the normalization steps enabled in opts to the clauses of
+// the formula. Clauses are updated in place and NumVars is left unchanged.
+func (f *CNFFormula) Normalize(opts NormalizeOptions) {
+	kept := f.Clauses[:0]
+	for _, c := range f.Clauses {
+		if opts.RemoveDuplicateLiterals {
+			c = uniqueLiterals(c)
+		}
+		if opts.SortLiterals {
+			sortLiterals(c)
+		}
+		if opts.RemoveTautologies && IsTautology(c) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	for i := len(kept); i < len(f.Clauses); i++ {
+		f.Clauses[i] = nil // allow removed clauses to be garbage collected
+	}
+	f.Clauses = kept
+
+	if opts.RemoveDuplicateClauses {
+		f.Dedup()
+	}
+}
+
+// UniqueLiteralsBuilder wraps a Builder and removes repeated literals from the
+// clauses before passing them to the wrapped Builder (e.g. [1 1 -2] becomes
+// [1 -2]). The first occurrence of each literal is kept and literals keep their
+// original order unless SortLiterals is set. Problem and comment lines are
+// forwarded unchanged.
+type UniqueLiteralsBuilder struct {
+	Builder
+
+	// SortLiterals sorts the literals of each clause by variable and then by
+	// sign before passing the clause to the wrapped Builder.
+	SortLiterals bool
+
+	buf []int
+}
+
+// Clause forwards the clause to the wrapped Builder without repeated literals.
+func (b *UniqueLiteralsBuilder) Clause(tmpClause []int) error {
+	b.buf = uniqueLiterals(append(b.buf[:0], tmpClause...))
+	if b.SortLiterals {
+		sortLiterals(b.buf)
+	}
+	return b.Builder.Clause(b.buf)
+}
+
+// smallClause is the clause length below which quadratic scans are preferred
+// to allocating a set of literals.
+const smallClause = 16
+
+// uniqueLiterals removes the repeated literals of c in place, keeping the first
+// occurrence of each literal, and returns the resulting slice.
+func uniqueLiterals(c []int) []int {
+	unique := c[:0]
+	if len(c) <= smallClause {
+		for _, l := range c {
+			if !containsLiteral(unique, l) {
+				unique = append(unique, l)
+			}
+		}
+		return unique
+	}
+	seen := make(map[int]struct{}, len(c))
+	for _, l := range c {
+		if _, ok := seen[l]; !ok {
+			seen[l] = struct{}{}
+			unique = append(unique, l)
+		}
+	}
+	return unique
+}
+
+// duplicateLiteral returns the first literal of c that appears more than once
+// in c, if any.
+func duplicateLiteral(c []int) (int, bool) {
+	if len(c) <= smallClause {
+		for i, l := range c {
+			if containsLiteral(c[i+1:], l) {
+				return l, true
+			}
+		}
+		return 0, false
+	}
+	seen := make(map[int]struct{}, len(c))
+	for _, l := range c {
+		if _, ok := seen[l]; ok {
+			return l, true
+		}
+		seen[l] = struct{}{}
+	}
+	return 0, false
+}
+
+// IsTautology returns true if clause c contains both a literal and its
+// negation (e.g. [1 -2 2]). Such a clause is always satisfied.
+func IsTautology(c []int) bool {
+	if len(c) <= smallClause {
+		for i, l := range c {
+			if containsLiteral(c[i+1:], -l) {
+				return true
+			}
+		}
+		return false
+	}
+	seen := make(map[int]struct{}, len(c))
+	for _, l := range c {
+		if _, ok := seen[-l]; ok {
+			return true
+		}
+		seen[l] = struct{}{}
+	}
+	return false
+}
+
+func containsLiteral(c []int, l int) bool {
+	for _, x := range c {
+		if x == l {
+			return true
+		}
+	}
+	return false
+}
+
+// sortLiterals sorts the literals of c by variable and then by sign.
+func sortLiterals(c []int) {
+	sort.Slice(c, func(i, j int) bool {
+		return lessLiteral(c[i], c[j])
+	})
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,598 @@
+package dimacs
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDedup(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		clauses     [][]int
+		wantClauses [][]int
+		wantRemoved int
+	}{
+		{
+			desc:        "empty formula",
+			clauses:     [][]int{},
+			wantClauses: [][]int{},
+			wantRemoved: 0,
+		},
+		{
+			desc:        "no duplicates",
+			clauses:     [][]int{{1, 2}, {-1, 2}, {3}},
+			wantClauses: [][]int{{1, 2}, {-1, 2}, {3}},
+			wantRemoved: 0,
+		},
+		{
+			desc:        "identical clauses",
+			clauses:     [][]int{{1, 2}, {3}, {1, 2}, {3}},
+			wantClauses: [][]int{{1, 2}, {3}},
+			wantRemoved: 2,
+		},
+		{
+			desc:        "different literal order",
+			clauses:     [][]int{{2, -1, 3}, {3, 2, -1}, {-1, 3, 2}},
+			wantClauses: [][]int{{2, -1, 3}},
+			wantRemoved: 2,
+		},
+		{
+			desc:        "empty clauses",
+			clauses:     [][]int{{}, {1}, {}},
+			wantClauses: [][]int{{}, {1}},
+			wantRemoved: 1,
+		},
+		{
+			desc:        "repeated literals",
+			clauses:     [][]int{{1, -2}, {1, 1, -2}, {-2, 1, 1}, {-2, 1}},
+			wantClauses: [][]int{{1, -2}, {1, 1, -2}},
+			wantRemoved: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := CNFFormula{NumVars: 3, Clauses: tc.clauses}
+
+			gotRemoved := f.Dedup()
+
+			if gotRemoved != tc.wantRemoved {
+				t.Errorf("Dedup(): want %d removed, got %d", tc.wantRemoved, gotRemoved)
+			}
+			if diff := cmp.Diff(tc.wantClauses, f.Clauses); diff != "" {
+				t.Errorf("Dedup(): clauses mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		clauses [][]int
+		want    [][2]int
+	}{
+		{
+			desc:    "empty formula",
+			clauses: [][]int{},
+			want:    nil,
+		},
+		{
+			desc:    "no duplicates",
+			clauses: [][]int{{1, 2}, {-1, 2}, {1, 1, 2}, {3}},
+			want:    nil,
+		},
+		{
+			desc:    "identical clauses",
+			clauses: [][]int{{1, 2}, {3}, {1, 2}, {3}, {1, 2}},
+			want:    [][2]int{{0, 2}, {1, 3}, {0, 4}},
+		},
+		{
+			desc:    "different literal order",
+			clauses: [][]int{{2, -1, 3}, {3, 2, -1}, {-1, 3, 2}},
+			want:    [][2]int{{0, 1}, {0, 2}},
+		},
+		{
+			desc:    "empty clauses",
+			clauses: [][]int{{1}, {}, {}},
+			want:    [][2]int{{1, 2}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := CNFFormula{NumVars: 3, Clauses: tc.clauses}
+			before := f.Clone()
+
+			got := f.FindDuplicates()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("FindDuplicates(): pairs mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(before, f); diff != "" {
+				t.Errorf("FindDuplicates(): formula modified (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDedupBuilder(t *testing.T) {
+	input := "p cnf 3 3\n1 2 0\n2 1 0\n-3 0\n"
+	cb := &cnfBuilder{}
+
+	if err := ReadBuilder(strings.NewReader(input), &DedupBuilder{Builder: cb}); err != nil {
+		t.Fatalf("ReadBuilder(): want no error, got %s", err)
+	}
+
+	want := [][]int{{1, 2}, {-3}}
+	if diff := cmp.Diff(want, cb.cnf.Clauses); diff != "" {
+		t.Errorf("DedupBuilder: clauses mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	clauses := func() [][]int {
+		return [][]int{{3, -1, 3}, {2, -2}, {-1, 3}, {1, 1}}
+	}
+
+	testCases := []struct {
+		desc        string
+		opts        NormalizeOptions
+		wantClauses [][]int
+	}{
+		{
+			desc:        "no options",
+			opts:        NormalizeOptions{},
+			wantClauses: [][]int{{3, -1, 3}, {2, -2}, {-1, 3}, {1, 1}},
+		},
+		{
+			desc:        "sort literals",
+			opts:        NormalizeOptions{SortLiterals: true},
+			wantClauses: [][]int{{-1, 3, 3}, {-2, 2}, {-1, 3}, {1, 1}},
+		},
+		{
+			desc:        "remove duplicate literals",
+			opts:        NormalizeOptions{RemoveDuplicateLiterals: true},
+			wantClauses: [][]int{{3, -1}, {2, -2}, {-1, 3}, {1}},
+		},
+		{
+			desc:        "remove tautologies",
+			opts:        NormalizeOptions{RemoveTautologies: true},
+			wantClauses: [][]int{{3, -1, 3}, {-1, 3}, {1, 1}},
+		},
+		{
+			desc: "remove duplicate clauses",
+			opts: NormalizeOptions{
+				RemoveDuplicateLiterals: true,
+				RemoveDuplicateClauses:  true,
+			},
+			wantClauses: [][]int{{3, -1}, {2, -2}, {1}},
+		},
+		{
+			desc: "all options",
+			opts: NormalizeOptions{
+				SortLiterals:            true,
+				RemoveDuplicateLiterals: true,
+				RemoveTautologies:       true,
+				RemoveDuplicateClauses:  true,
+			},
+			wantClauses: [][]int{{-1, 3}, {1}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := CNFFormula{NumVars: 3, Clauses: clauses()}
+
+			f.Normalize(tc.opts)
+
+			if f.NumVars != 3 {
+				t.Errorf("Normalize(): want NumVars 3, got %d", f.NumVars)
+			}
+			if diff := cmp.Diff(tc.wantClauses, f.Clauses); diff != "" {
+				t.Errorf("Normalize(): clauses mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRemoveTautologies(t *testing.T) {
+	long := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18}
+	longTautology := append([]int{-18}, long...)
+
+	testCases := []struct {
+		desc        string
+		clauses     [][]int
+		wantClauses [][]int
+		wantRemoved int
+	}{
+		{
+			desc:        "empty formula",
+			clauses:     [][]int{},
+			wantClauses: [][]int{},
+			wantRemoved: 0,
+		},
+		{
+			desc:        "tautology",
+			clauses:     [][]int{{1, -1, 2}, {2, 3}},
+			wantClauses: [][]int{{2, 3}},
+			wantRemoved: 1,
+		},
+		{
+			desc:        "self-duplicated literals",
+			clauses:     [][]int{{1, 1, 2}, {-3, -3}},
+			wantClauses: [][]int{{1, 1, 2}, {-3, -3}},
+			wantRemoved: 0,
+		},
+		{
+			desc:        "self-duplicated literals and tautology",
+			clauses:     [][]int{{2, 2, -2}, {-3, -3}},
+			wantClauses: [][]int{{-3, -3}},
+			wantRemoved: 1,
+		},
+		{
+			desc:        "long clauses",
+			clauses:     [][]int{long, longTautology},
+			wantClauses: [][]int{long},
+			wantRemoved: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := CNFFormula{NumVars: 18, Clauses: tc.clauses}
+
+			gotRemoved := f.RemoveTautologies()
+
+			if gotRemoved != tc.wantRemoved {
+				t.Errorf("RemoveTautologies(): want %d removed, got %d", tc.wantRemoved, gotRemoved)
+			}
+			if diff := cmp.Diff(tc.wantClauses, f.Clauses); diff != "" {
+				t.Errorf("RemoveTautologies(): clauses mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRemoveSubsumed(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		clauses     [][]int
+		wantClauses [][]int
+		wantRemoved int
+	}{
+		{
+			desc:        "empty formula",
+			clauses:     [][]int{},
+			wantClauses: [][]int{},
+			wantRemoved: 0,
+		},
+		{
+			desc:        "no subsumption",
+			clauses:     [][]int{{1, 2}, {-1, 2}, {1, -2}},
+			wantClauses: [][]int{{1, 2}, {-1, 2}, {1, -2}},
+			wantRemoved: 0,
+		},
+		{
+			desc:        "subsumed by later clause",
+			clauses:     [][]int{{3, -2, 1}, {4}, {1, -2}},
+			wantClauses: [][]int{{4}, {1, -2}},
+			wantRemoved: 1,
+		},
+		{
+			desc:        "overlapping clauses",
+			clauses:     [][]int{{1, 2, 3}, {2, 3}, {1, 3}, {3, 4}, {3}, {-3, 1}},
+			wantClauses: [][]int{{3}, {-3, 1}},
+			wantRemoved: 4,
+		},
+		{
+			desc:        "chain of subsets",
+			clauses:     [][]int{{1, 2, 3, 4}, {1, 2, 3}, {1, 2}, {2, 5}},
+			wantClauses: [][]int{{1, 2}, {2, 5}},
+			wantRemoved: 2,
+		},
+		{
+			desc:        "identical clauses",
+			clauses:     [][]int{{2, 1}, {1, 2}, {1, 2, 1}},
+			wantClauses: [][]int{{2, 1}},
+			wantRemoved: 2,
+		},
+		{
+			desc:        "empty clause",
+			clauses:     [][]int{{1}, {}, {-1, 2}, {}},
+			wantClauses: [][]int{{}},
+			wantRemoved: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := CNFFormula{NumVars: 5, Clauses: tc.clauses}
+
+			gotRemoved := f.RemoveSubsumed()
+
+			if gotRemoved != tc.wantRemoved {
+				t.Errorf("RemoveSubsumed(): want %d removed, got %d", tc.wantRemoved, gotRemoved)
+			}
+			if diff := cmp.Diff(tc.wantClauses, f.Clauses); diff != "" {
+				t.Errorf("RemoveSubsumed(): clauses mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRemoveSubsumed_bruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	clauses := make([][]int, 300)
+	for i := range clauses {
+		clauses[i] = make([]int, rng.Intn(4)+1)
+		for j := range clauses[i] {
+			clauses[i][j] = rng.Intn(6) + 1
+			if rng.Intn(2) == 0 {
+				clauses[i][j] = -clauses[i][j]
+			}
+		}
+	}
+
+	// A clause is kept if no other clause is a strict subset of it and no
+	// identical clause appears before it.
+	want := [][]int{}
+	for i, b := range clauses {
+		sb := uniqueLiterals(append([]int(nil), b...))
+		sort.Ints(sb)
+		subsumed := false
+		for j, a := range clauses {
+			sa := uniqueLiterals(append([]int(nil), a...))
+			sort.Ints(sa)
+			if j != i && isSubset(sa, sb) && (len(sa) < len(sb) || j < i) {
+				subsumed = true
+				break
+			}
+		}
+		if !subsumed {
+			want = append(want, b)
+		}
+	}
+
+	f := CNFFormula{NumVars: 6, Clauses: clauses}
+	f.RemoveSubsumed()
+
+	if diff := cmp.Diff(want, f.Clauses); diff != "" {
+		t.Errorf("RemoveSubsumed(): clauses mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTautologyFilterBuilder(t *testing.T) {
+	input := "p cnf 3 3\n1 -1 2 0\n2 3 0\n-3 3 0\n"
+	cb := &cnfBuilder{}
+
+	if err := ReadBuilder(strings.NewReader(input), &TautologyFilterBuilder{cb}); err != nil {
+		t.Fatalf("ReadBuilder(): want no error, got %s", err)
+	}
+
+	want := [][]int{{2, 3}}
+	if diff := cmp.Diff(want, cb.cnf.Clauses); diff != "" {
+		t.Errorf("TautologyFilterBuilder: clauses mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestUniqueLiteralsBuilder(t *testing.T) {
+	input := "p cnf 3 3\n3 1 3 -2 0\n1 1 0\n-2 -1 0\n"
+
+	testCases := []struct {
+		desc        string
+		sort        bool
+		wantClauses [][]int
+	}{
+		{
+			desc:        "first occurrence order",
+			sort:        false,
+			wantClauses: [][]int{{3, 1, -2}, {1}, {-2, -1}},
+		},
+		{
+			desc:        "sorted",
+			sort:        true,
+			wantClauses: [][]int{{1, -2, 3}, {1}, {-1, -2}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cb := &cnfBuilder{}
+			b := &UniqueLiteralsBuilder{Builder: cb, SortLiterals: tc.sort}
+
+			if err := ReadBuilder(strings.NewReader(input), b); err != nil {
+				t.Fatalf("ReadBuilder(): want no error, got %s", err)
+			}
+
+			if diff := cmp.Diff(tc.wantClauses, cb.cnf.Clauses); diff != "" {
+				t.Errorf("UniqueLiteralsBuilder: clauses mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUsedVariables(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		formula CNFFormula
+		want    []int
+	}{
+		{
+			desc:    "empty formula",
+			formula: CNFFormula{NumVars: 10},
+			want:    []int{},
+		},
+		{
+			desc: "gaps",
+			formula: CNFFormula{
+				NumVars: 100,
+				Clauses: [][]int{{42, -7}, {}, {7, -100, 3}, {-42}},
+			},
+			want: []int{3, 7, 42, 100},
+		},
+		{
+			desc: "under-declared variables",
+			formula: CNFFormula{
+				NumVars: 1,
+				Clauses: [][]int{{1, -3}},
+			},
+			want: []int{1, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tc.formula.UsedVariables()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("UsedVariables(): mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompact(t *testing.T) {
+	f := CNFFormula{
+		NumVars: 10,
+		Clauses: [][]int{{5, -1}, {9}, {-9, -5}, {}},
+	}
+
+	gotMapping := f.Compact()
+
+	wantMapping := map[int]int{1: 1, 5: 2, 9: 3}
+	if diff := cmp.Diff(wantMapping, gotMapping); diff != "" {
+		t.Errorf("Compact(): mapping mismatch (-want +got):\n%s", diff)
+	}
+	want := CNFFormula{
+		NumVars: 3,
+		Clauses: [][]int{{2, -1}, {3}, {-3, -2}, {}},
+	}
+	if diff := cmp.Diff(want, f); diff != "" {
+		t.Errorf("Compact(): CNF mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompacted(t *testing.T) {
+	f := CNFFormula{
+		NumVars: 10,
+		Clauses: [][]int{{5, -1}, {9}, {-9, -5}, {}},
+	}
+	original := f.Clone()
+
+	got, gotMapping := f.Compacted()
+
+	wantMapping := map[int]int{1: 1, 5: 2, 9: 3}
+	if diff := cmp.Diff(wantMapping, gotMapping); diff != "" {
+		t.Errorf("Compacted(): mapping mismatch (-want +got):\n%s", diff)
+	}
+	want := CNFFormula{
+		NumVars: 3,
+		Clauses: [][]int{{2, -1}, {3}, {-3, -2}, {}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Compacted(): CNF mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(original, f); diff != "" {
+		t.Errorf("Compacted(): original formula modified (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompact_roundTrip(t *testing.T) {
+	original := [][]int{{7, -3}, {-7, 12, 3}, {-12}}
+	f := CNFFormula{NumVars: 12, Clauses: [][]int{{7, -3}, {-7, 12, 3}, {-12}}}
+
+	mapping := f.Compact()
+
+	inverse := map[int]int{}
+	for oldVar, newVar := range mapping {
+		inverse[newVar] = oldVar
+	}
+	for _, c := range f.Clauses {
+		for i, l := range c {
+			if l < 0 {
+				c[i] = -inverse[-l]
+			} else {
+				c[i] = inverse[l]
+			}
+		}
+	}
+	if diff := cmp.Diff(original, f.Clauses); diff != "" {
+		t.Errorf("Compact(): inverted clauses mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIsTautology(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		clause []int
+		want   bool
+	}{
+		{desc: "empty clause", clause: []int{}, want: false},
+		{desc: "unit clause", clause: []int{2}, want: false},
+		{desc: "tautology", clause: []int{1, 2, -3, -2}, want: true},
+		{desc: "repeated literal", clause: []int{2, 1, 2}, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := IsTautology(tc.clause); got != tc.want {
+				t.Errorf("IsTautology(%v): want %t, got %t", tc.clause, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHasTautologyAndEmptyClause(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		clauses         [][]int
+		wantTautology   bool
+		wantEmptyClause bool
+	}{
+		{
+			desc:            "no clauses",
+			clauses:         [][]int{},
+			wantTautology:   false,
+			wantEmptyClause: false,
+		},
+		{
+			desc:            "tautology",
+			clauses:         [][]int{{1, 3}, {2, -1, -2}},
+			wantTautology:   true,
+			wantEmptyClause: false,
+		},
+		{
+			desc:            "empty clause",
+			clauses:         [][]int{{1, 3}, {}},
+			wantTautology:   false,
+			wantEmptyClause: true,
+		},
+		{
+			desc:            "tautology and empty clause",
+			clauses:         [][]int{{}, {-2, 2}},
+			wantTautology:   true,
+			wantEmptyClause: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := CNFFormula{NumVars: 3, Clauses: tc.clauses}
+
+			if got := f.HasTautology(); got != tc.wantTautology {
+				t.Errorf("HasTautology(): want %t, got %t", tc.wantTautology, got)
+			}
+			if got := f.HasEmptyClause(); got != tc.wantEmptyClause {
+				t.Errorf("HasEmptyClause(): want %t, got %t", tc.wantEmptyClause, got)
+			}
+			if got := f.IsTriviallyUnsat(); got != tc.wantEmptyClause {
+				t.Errorf("IsTriviallyUnsat(): want %t, got %t", tc.wantEmptyClause, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package dimacs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SolutionStatus is the answer reported by a SAT solver on its "s" line.
+type SolutionStatus int
+
+const (
+	StatusUnknown SolutionStatus = iota
+	StatusSatisfiable
+	StatusUnsatisfiable
+)
+
+func (s SolutionStatus) String() string {
+	switch s {
+	case StatusSatisfiable:
+		return "SATISFIABLE"
+	case StatusUnsatisfiable:
+		return "UNSATISFIABLE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Solution represents the output of a SAT solver in the format used by the SAT
+// competitions.
+type Solution struct {
+	Status SolutionStatus
+
+	// Assignment holds the value of each variable as reported by the "v" lines:
+	// Assignment[i] is i+1 if variable i+1 is true, -(i+1) if it is false, and
+	// 0 if the solver did not report its value. The slice is only long enough
+	// to hold the largest reported variable, which can be less than the number
+	// of variables of the formula. It is nil if there are no "v" lines.
+	Assignment []int
+}
+
+// ReadSolution parses the output of a SAT solver from the given reader. The
+// output must contain exactly one status line ("s SATISFIABLE",
+// "s UNSATISFIABLE", or "s UNKNOWN"). Satisfiable solutions can be followed by
+// value lines (e.g. "v 1 -2 3 0") listing the literals of the model, possibly
+// spread over several lines, the last one being terminated by a 0. Comment
+// lines (starting with "c") and any other line are ignored.
+//
+// Parsing errors are reported as *ParseError. The status line plays the role of
+// the problem line: a missing, duplicate or malformed status line is reported
+// with ErrNoProblemLine, ErrDuplicateProblem or ErrInvalidProblemLine.
+func ReadSolution(r io.Reader) (Solution, error) {
+	rd := Reader{}
+	rd.Reset(r)
+	sol := Solution{}
+	hasStatus := false
+	terminated := false
+	var lits []int
+
+	n := 1
+	for ; ; n++ {
+		line, err := rd.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Solution{}, err
+		}
+		if n == 1 {
+			line = bytes.TrimPrefix(line, []byte(utf8BOM))
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || (len(line) > 1 && !isSpace(line[1])) {
+			continue // not a status or value line
+		}
+
+		switch line[0] {
+		case 's':
+			if hasStatus {
+				err := fmt.Errorf("%w: duplicate status line", ErrDuplicateProblem)
+				return Solution{}, newParseError(n, string(line), err)
+			}
+			status, err := parseStatus(string(line))
+			if err != nil {
+				return Solution{}, newParseError(n, string(line), err)
+			}
+			sol.Status = status
+			hasStatus = true
+		case 'v':
+			if terminated {
+				err := fmt.Errorf("value line after the terminating 0")
+				return Solution{}, newParseError(n, string(line), err)
+			}
+			lits, terminated, err = parseClause(line[1:], lits)
+			if err != nil {
+				return Solution{}, newParseError(n, string(line), err)
+			}
+		}
+	}
+	lastLine := n - 1
+	if !hasStatus {
+		err := fmt.Errorf("%w: missing status line", ErrNoProblemLine)
+		return Solution{}, newParseError(lastLine, "", err)
+	}
+	if lits == nil && !terminated {
+		return sol, nil
+	}
+	if sol.Status != StatusSatisfiable {
+		err := fmt.Errorf("value lines in %s solution", sol.Status)
+		return Solution{}, newParseError(lastLine, "", err)
+	}
+	if !terminated {
+		err := fmt.Errorf("%w: value lines", ErrUnterminatedClause)
+		return Solution{}, newParseError(lastLine, "", err)
+	}
+
+	sol.Assignment = make([]int, maxVar([][]int{lits}))
+	for _, l := range lits {
+		v := abs(l)
+		if a := sol.Assignment[v-1]; a != 0 && a != l {
+			err := fmt.Errorf("conflicting values for variable %d", v)
+			return Solution{}, newParseError(lastLine, "", err)
+		}
+		sol.Assignment[v-1] = l
+	}
+	return sol, nil
+}
+
+// parseStatus parses a "s <status>" line.
+func parseStatus(line string) (SolutionStatus, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 || fields[0] != "s" {
+		return StatusUnknown, fmt.Errorf("%w: invalid status line %q", ErrInvalidProblemLine, line)
+	}
+	switch fields[1] {
+	case "SATISFIABLE":
+		return StatusSatisfiable, nil
+	case "UNSATISFIABLE":
+		return StatusUnsatisfiable, nil
+	case "UNKNOWN":
+		return StatusUnknown, nil
+	}
+	return StatusUnknown, fmt.Errorf("%w: invalid status %q", ErrInvalidProblemLine, fields[1])
+}
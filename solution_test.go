@@ -0,0 +1,175 @@
+package dimacs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const validSolution = `c solver output
+c some statistics
+s SATISFIABLE
+v 1 -2
+v -4 3
+v 0
+`
+
+func TestReadSolution(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		input        string
+		wantSolution Solution
+		wantErr      bool
+	}{
+		{
+			desc:         "byte order mark",
+			input:        "\ufeffs UNSATISFIABLE\n",
+			wantSolution: Solution{Status: StatusUnsatisfiable},
+			wantErr:      false,
+		},
+		{
+			desc:  "satisfiable",
+			input: validSolution,
+			wantSolution: Solution{
+				Status:     StatusSatisfiable,
+				Assignment: []int{1, -2, 3, -4},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "partial assignment",
+			input: "s SATISFIABLE\nv -3 1 0\n",
+			wantSolution: Solution{
+				Status:     StatusSatisfiable,
+				Assignment: []int{1, 0, -3},
+			},
+			wantErr: false,
+		},
+		{
+			desc:         "satisfiable without values",
+			input:        "s SATISFIABLE\n",
+			wantSolution: Solution{Status: StatusSatisfiable},
+			wantErr:      false,
+		},
+		{
+			desc:         "unsatisfiable",
+			input:        "c unsat\ns UNSATISFIABLE\n",
+			wantSolution: Solution{Status: StatusUnsatisfiable},
+			wantErr:      false,
+		},
+		{
+			desc:         "unknown",
+			input:        "s UNKNOWN\n",
+			wantSolution: Solution{Status: StatusUnknown},
+			wantErr:      false,
+		},
+		{
+			desc:         "other lines",
+			input:        "solving...\nvars: 3\ns UNSATISFIABLE\n",
+			wantSolution: Solution{Status: StatusUnsatisfiable},
+			wantErr:      false,
+		},
+		{
+			desc:    "missing status",
+			input:   "v 1 2 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "duplicate status",
+			input:   "s SATISFIABLE\ns SATISFIABLE\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid status",
+			input:   "s SAT\n",
+			wantErr: true,
+		},
+		{
+			desc:    "values in unsatisfiable solution",
+			input:   "s UNSATISFIABLE\nv 1 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "values not terminated",
+			input:   "s SATISFIABLE\nv 1 2\n",
+			wantErr: true,
+		},
+		{
+			desc:    "values after terminating zero",
+			input:   "s SATISFIABLE\nv 1 0\nv 2 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "conflicting values",
+			input:   "s SATISFIABLE\nv 1 2 -1 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid value",
+			input:   "s SATISFIABLE\nv 1 x 0\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotErr := ReadSolution(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadSolution(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadSolution(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantSolution, got); diff != "" {
+				t.Errorf("ReadSolution(): solution mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadSolution_satisfies(t *testing.T) {
+	f, err := ReadCNF(strings.NewReader("p cnf 4 3\n1 2 0\n-2 3 0\n-4 0\n"))
+	if err != nil {
+		t.Fatalf("ReadCNF(): want no error, got %s", err)
+	}
+
+	sol, err := ReadSolution(strings.NewReader(validSolution))
+	if err != nil {
+		t.Fatalf("ReadSolution(): want no error, got %s", err)
+	}
+
+	got, err := f.Satisfies(sol.Assignment)
+	if err != nil {
+		t.Fatalf("Satisfies(): want no error, got %s", err)
+	}
+	if !got {
+		t.Errorf("Satisfies(): want true, got false")
+	}
+}
+
+func TestReadSolution_longValueLine(t *testing.T) {
+	const nVars = 20000
+	sb := strings.Builder{}
+	sb.WriteString("s SATISFIABLE\nv")
+	want := make([]int, nVars)
+	for v := 1; v <= nVars; v++ {
+		want[v-1] = v
+		if v%3 == 0 {
+			want[v-1] = -v
+		}
+		fmt.Fprintf(&sb, " %d", want[v-1])
+	}
+	sb.WriteString(" 0\n")
+
+	sol, err := ReadSolution(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatalf("ReadSolution(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff(want, sol.Assignment); diff != "" {
+		t.Errorf("ReadSolution(): assignment mismatch (-want +got):\n%s", diff)
+	}
+}
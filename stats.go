@@ -0,0 +1,271 @@
+package dimacs
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Stats holds summary statistics about a CNF formula.
+type Stats struct {
+	NumClauses      int
+	NumLiterals     int // total number of literals over all clauses
+	MinClauseLen    int
+	MaxClauseLen    int
+	AvgClauseLen    float64
+	NumUnitClauses  int
+	NumEmptyClauses int
+
+	// NumBinaryClauses and NumTernaryClauses are the number of clauses of
+	// length 2 and 3, which many solvers handle specially. The full
+	// distribution of clause lengths is given by ClauseLengthHistogram.
+	NumBinaryClauses  int
+	NumTernaryClauses int
+
+	// PosOccurrences[v] and NegOccurrences[v] are the number of occurrences
+	// of the positive and negative literals of variable v. Both slices have
+	// length NumVars+1 and their index 0 is unused.
+	PosOccurrences []int
+	NegOccurrences []int
+}
+
+// Stats computes and returns statistics about the formula. Clause lengths
+// count repeated literals. Min, max and average clause lengths are 0 for a
+// formula without clauses.
+func (f CNFFormula) Stats() Stats {
+	numVars := f.NumVars
+	if v := maxVar(f.Clauses); v > numVars {
+		numVars = v // be robust to formulas that under-declare variables
+	}
+	s := Stats{
+		NumClauses:     len(f.Clauses),
+		PosOccurrences: make([]int, numVars+1),
+		NegOccurrences: make([]int, numVars+1),
+	}
+	for i, c := range f.Clauses {
+		switch len(c) {
+		case 0:
+			s.NumEmptyClauses++
+		case 1:
+			s.NumUnitClauses++
+		case 2:
+			s.NumBinaryClauses++
+		case 3:
+			s.NumTernaryClauses++
+		}
+		if i == 0 || len(c) < s.MinClauseLen {
+			s.MinClauseLen = len(c)
+		}
+		if len(c) > s.MaxClauseLen {
+			s.MaxClauseLen = len(c)
+		}
+		s.NumLiterals += len(c)
+		for _, l := range c {
+			if l > 0 {
+				s.PosOccurrences[l]++
+			} else {
+				s.NegOccurrences[-l]++
+			}
+		}
+	}
+	if s.NumClauses > 0 {
+		s.AvgClauseLen = float64(s.NumLiterals) / float64(s.NumClauses)
+	}
+	return s
+}
+
+// ClauseLengthHistogram returns a map from clause length to the number of
+// clauses of that length. Empty clauses are counted under key 0 and clause
+// lengths count repeated literals, as in Stats.
+func (f CNFFormula) ClauseLengthHistogram() map[int]int {
+	hist := map[int]int{}
+	for _, c := range f.Clauses {
+		hist[len(c)]++
+	}
+	return hist
+}
+
+// MaxVar returns the largest variable appearing in the clauses of the
+// formula, or 0 if there is none. It can differ from NumVars if the last
+// declared variables are unused or if the formula under-declares its
+// variables.
+func (f CNFFormula) MaxVar() int {
+	return maxVar(f.Clauses)
+}
+
+// maxVar returns the largest variable appearing in the given clauses, or 0 if
+// there is none.
+func maxVar(clauses [][]int) int {
+	m := 0
+	for _, c := range clauses {
+		for _, l := range c {
+			if v := abs(l); v > m {
+				m = v
+			}
+		}
+	}
+	return m
+}
+
+// VarStat holds the number of occurrences of the positive and negative
+// literals of a variable.
+type VarStat struct {
+	Pos int
+	Neg int
+}
+
+// Occurrences returns the number of positive and negative occurrences of each
+// variable in the formula. The returned slice is indexed by variable, index 0
+// being unused.
+func (f CNFFormula) Occurrences() []VarStat {
+	numVars := f.NumVars
+	if v := maxVar(f.Clauses); v > numVars {
+		numVars = v
+	}
+	occs := make([]VarStat, numVars+1)
+	for _, c := range f.Clauses {
+		occs = addOccurrences(occs, c)
+	}
+	return occs
+}
+
+// VariableOccurrences returns, for each variable, the indices of the clauses in
+// which it appears regardless of its polarity, in increasing order. The
+// returned slice is indexed by variable, index 0 being unused, and the entry
+// of a variable that does not appear in any clause is nil. Together with
+// ClauseVariables, it represents the variable-clause incidence graph of the
+// formula.
+func (f CNFFormula) VariableOccurrences() [][]int {
+	numVars := f.NumVars
+	if v := maxVar(f.Clauses); v > numVars {
+		numVars = v
+	}
+	occs := make([][]int, numVars+1)
+	for i, c := range f.Clauses {
+		for _, l := range c {
+			v := abs(l)
+			if n := len(occs[v]); n == 0 || occs[v][n-1] != i {
+				occs[v] = append(occs[v], i)
+			}
+		}
+	}
+	return occs
+}
+
+// ClauseVariables returns the variables of each clause, in order of first
+// appearance in the clause and without repetition (e.g. [-2 1 2] has variables
+// [2 1]). The returned slice has one entry per clause.
+func (f CNFFormula) ClauseVariables() [][]int {
+	vars := make([][]int, len(f.Clauses))
+	for i, c := range f.Clauses {
+		vs := make([]int, len(c))
+		for j, l := range c {
+			vs[j] = abs(l)
+		}
+		vars[i] = uniqueLiterals(vs)
+	}
+	return vars
+}
+
+// Occurrences reads a DIMACS CNF file from r and returns the number of
+// positive and negative occurrences of each variable, indexed by variable
+// (index 0 being unused), without storing the clauses. The file is validated
+// as in ReadCNF.
+func Occurrences(r io.Reader) ([]VarStat, error) {
+	b := occurrencesBuilder{}
+	lines, err := readBuilder(context.Background(), r, &b, ReadOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if b.occs == nil {
+		return nil, newParseError(lines, "", ErrNoProblemLine)
+	}
+	if b.nRead < b.nClauses {
+		return nil, newParseError(lines, "", &ClauseCountError{Declared: b.nClauses, Actual: b.nRead})
+	}
+	return b.occs, nil
+}
+
+type occurrencesBuilder struct {
+	occs     []VarStat
+	nClauses int
+	nRead    int
+}
+
+func (b *occurrencesBuilder) Problem(p string, v int, c int) error {
+	if b.occs != nil {
+		return ErrDuplicateProblem
+	}
+	if p != "cnf" {
+		return fmt.Errorf("%w: expected \"cnf\" problem, got %q", ErrInvalidProblemType, p)
+	}
+	if v < 0 || c < 0 {
+		return fmt.Errorf("%w: counts must be non-negative, got: %d %d", ErrInvalidProblemLine, v, c)
+	}
+	b.occs = make([]VarStat, v+1)
+	b.nClauses = c
+	return nil
+}
+
+func (b *occurrencesBuilder) Clause(tmp []int) error {
+	if b.occs == nil {
+		return ErrClauseBeforeProblem
+	}
+	if b.nRead == b.nClauses {
+		return &ClauseCountError{Declared: b.nClauses, Actual: b.nRead + 1, TooMany: true}
+	}
+	b.nRead++
+	b.occs = addOccurrences(b.occs, tmp)
+	return nil
+}
+
+func (b *occurrencesBuilder) Comment(_ string) error { return nil } // ignore comments
+
+// addOccurrences counts the literals of clause c in occs, growing occs if c
+// contains variables beyond its length, and returns the updated slice.
+func addOccurrences(occs []VarStat, c []int) []VarStat {
+	for _, l := range c {
+		v := abs(l)
+		for v >= len(occs) {
+			occs = append(occs, VarStat{})
+		}
+		if l > 0 {
+			occs[v].Pos++
+		} else {
+			occs[v].Neg++
+		}
+	}
+	return occs
+}
+
+// PureLiterals returns the pure literals of the formula, that is the literals
+// whose variable only appears with a single polarity: v if v only appears
+// positively and -v if v only appears negatively. Variables that do not appear
+// in the formula are excluded. Literals are returned in increasing order of
+// their variable.
+func (f CNFFormula) PureLiterals() []int {
+	return pureLiterals(f.Occurrences())
+}
+
+// PureLiterals reads a DIMACS CNF file from r and returns its pure literals
+// (see CNFFormula.PureLiterals) without storing the clauses.
+func PureLiterals(r io.Reader) ([]int, error) {
+	occs, err := Occurrences(r)
+	if err != nil {
+		return nil, err
+	}
+	return pureLiterals(occs), nil
+}
+
+func pureLiterals(occs []VarStat) []int {
+	pure := []int{}
+	for v, o := range occs {
+		switch {
+		case o.Pos > 0 && o.Neg == 0:
+			pure = append(pure, v)
+		case o.Neg > 0 && o.Pos == 0:
+			pure = append(pure, -v)
+		}
+	}
+	return pure
+}
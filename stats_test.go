@@ -0,0 +1,359 @@
+package dimacs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStats(t *testing.T) {
+	f, err := ReadCNF(strings.NewReader(validCNF_noComments))
+	if err != nil {
+		t.Fatalf("ReadCNF(): want no error, got %s", err)
+	}
+
+	got := f.Stats()
+
+	want := Stats{
+		NumClauses:        4,
+		NumLiterals:       10,
+		MinClauseLen:      2,
+		MaxClauseLen:      3,
+		AvgClauseLen:      2.5,
+		NumUnitClauses:    0,
+		NumEmptyClauses:   0,
+		NumBinaryClauses:  2,
+		NumTernaryClauses: 2,
+		PosOccurrences:    []int{0, 3, 1, 2},
+		NegOccurrences:    []int{0, 0, 2, 2},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Stats(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStats_edgeCases(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		formula CNFFormula
+		want    Stats
+	}{
+		{
+			desc:    "empty formula",
+			formula: CNFFormula{},
+			want: Stats{
+				PosOccurrences: []int{0},
+				NegOccurrences: []int{0},
+			},
+		},
+		{
+			desc: "unit and empty clauses",
+			formula: CNFFormula{
+				NumVars: 2,
+				Clauses: [][]int{{}, {-2}, {1}, {1, 2, -2}},
+			},
+			want: Stats{
+				NumClauses:        4,
+				NumLiterals:       5,
+				MinClauseLen:      0,
+				MaxClauseLen:      3,
+				AvgClauseLen:      1.25,
+				NumUnitClauses:    2,
+				NumEmptyClauses:   1,
+				NumTernaryClauses: 1,
+				PosOccurrences:    []int{0, 2, 1},
+				NegOccurrences:    []int{0, 0, 2},
+			},
+		},
+		{
+			desc: "under-declared variables",
+			formula: CNFFormula{
+				NumVars: 1,
+				Clauses: [][]int{{1, -3}},
+			},
+			want: Stats{
+				NumClauses:       1,
+				NumLiterals:      2,
+				MinClauseLen:     2,
+				MaxClauseLen:     2,
+				AvgClauseLen:     2,
+				NumBinaryClauses: 1,
+				PosOccurrences:   []int{0, 1, 0, 0},
+				NegOccurrences:   []int{0, 0, 0, 1},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tc.formula.Stats()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Stats(): mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMaxVar(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		formula CNFFormula
+		want    int
+	}{
+		{
+			desc:    "empty formula",
+			formula: CNFFormula{},
+			want:    0,
+		},
+		{
+			desc:    "empty clauses",
+			formula: CNFFormula{NumVars: 2, Clauses: [][]int{{}, {}}},
+			want:    0,
+		},
+		{
+			desc:    "negative literal",
+			formula: CNFFormula{NumVars: 4, Clauses: [][]int{{1, -3}, {2}}},
+			want:    3,
+		},
+		{
+			desc:    "under-declared variables",
+			formula: CNFFormula{NumVars: 1, Clauses: [][]int{{1}, {5, -2}}},
+			want:    5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := tc.formula.MaxVar(); got != tc.want {
+				t.Errorf("MaxVar(): want %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestClauseLengthHistogram(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		formula CNFFormula
+		want    map[int]int
+	}{
+		{
+			desc:    "empty formula",
+			formula: CNFFormula{},
+			want:    map[int]int{},
+		},
+		{
+			desc: "mixed lengths",
+			formula: CNFFormula{
+				NumVars: 3,
+				Clauses: [][]int{{}, {1}, {1, 2, 3}, {-2}, {1, 1, -3}, {2, 3}},
+			},
+			want: map[int]int{0: 1, 1: 2, 2: 1, 3: 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tc.formula.ClauseLengthHistogram()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ClauseLengthHistogram(): mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	want := []VarStat{{}, {Pos: 3}, {Pos: 1, Neg: 2}, {Pos: 2, Neg: 2}}
+
+	f, err := ReadCNF(strings.NewReader(validCNF_noComments))
+	if err != nil {
+		t.Fatalf("ReadCNF(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(want, f.Occurrences()); diff != "" {
+		t.Errorf("CNFFormula.Occurrences(): mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err := Occurrences(strings.NewReader(validCNF_noComments))
+	if err != nil {
+		t.Fatalf("Occurrences(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Occurrences(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestOccurrences_streaming(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		input    string
+		wantOccs []VarStat
+		wantErr  bool
+	}{
+		{
+			desc:     "under-declared variables",
+			input:    "p cnf 1 2\n1 0\n-3 0\n",
+			wantOccs: []VarStat{{}, {Pos: 1}, {}, {Neg: 1}},
+			wantErr:  false,
+		},
+		{
+			desc:    "missing problem line",
+			input:   "c comment\n",
+			wantErr: true,
+		},
+		{
+			desc:    "clause before problem line",
+			input:   "1 0\np cnf 1 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "duplicate problem line",
+			input:   "p cnf 1 1\np cnf 1 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid problem type",
+			input:   "p sat 1 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "negative counts",
+			input:   "p cnf -1 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "too many clauses",
+			input:   "p cnf 1 1\n1 0\n-1 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "missing clauses",
+			input:   "p cnf 1 2\n1 0\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotOccs, gotErr := Occurrences(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("Occurrences(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("Occurrences(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.wantOccs, gotOccs); diff != "" {
+				t.Errorf("Occurrences(): mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVariableOccurrences(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		formula CNFFormula
+		want    [][]int
+	}{
+		{
+			desc:    "empty formula",
+			formula: CNFFormula{},
+			want:    [][]int{nil},
+		},
+		{
+			desc: "both polarities",
+			formula: CNFFormula{
+				NumVars: 4,
+				Clauses: [][]int{{1, -2}, {2, 3}, {}, {-1, -3, 1}},
+			},
+			want: [][]int{nil, {0, 3}, {0, 1}, {1, 3}, nil},
+		},
+		{
+			desc: "under-declared variables",
+			formula: CNFFormula{
+				NumVars: 1,
+				Clauses: [][]int{{1, -3}},
+			},
+			want: [][]int{nil, {0}, nil, {0}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tc.formula.VariableOccurrences()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("VariableOccurrences(): mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClauseVariables(t *testing.T) {
+	f := CNFFormula{
+		NumVars: 3,
+		Clauses: [][]int{{1, -2}, {}, {-3, 1, 3}, {2, 2}},
+	}
+
+	got := f.ClauseVariables()
+
+	want := [][]int{{1, 2}, {}, {3, 1}, {2}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ClauseVariables(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPureLiterals(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		formula CNFFormula
+		want    []int
+	}{
+		{
+			desc:    "empty formula",
+			formula: CNFFormula{NumVars: 2},
+			want:    []int{},
+		},
+		{
+			desc:    "no pure literals",
+			formula: CNFFormula{NumVars: 2, Clauses: [][]int{{1, 2}, {-1, -2}}},
+			want:    []int{},
+		},
+		{
+			desc: "pure literals",
+			formula: CNFFormula{
+				NumVars: 5,
+				Clauses: [][]int{{1, -2}, {-1, -4}, {5, -2}, {-4}},
+			},
+			want: []int{-2, -4, 5},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tc.formula.PureLiterals()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("PureLiterals(): mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPureLiterals_streaming(t *testing.T) {
+	got, err := PureLiterals(strings.NewReader(validCNF_noComments))
+	if err != nil {
+		t.Fatalf("PureLiterals(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff([]int{1}, got); diff != "" {
+		t.Errorf("PureLiterals(): mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := PureLiterals(strings.NewReader("")); err == nil {
+		t.Errorf("PureLiterals(): want error, got nil")
+	}
+}
@@ -0,0 +1,187 @@
+package dimacs
+
+import "fmt"
+
+// ToCNF converts the formula into an equisatisfiable CNF formula with the
+// Tseitin transformation: an auxiliary variable is introduced for each
+// conjunction, disjunction and exclusive disjunction of the expression along
+// with clauses stating that it is equivalent to its subexpression. A nil
+// expression is true.
+//
+// The variables of the formula are kept as is and the auxiliary variables are
+// numbered from firstAux to cnf.NumVars (inclusive), with firstAux equal to
+// f.NumVars+1. The range is empty if no auxiliary variable was needed. Because
+// auxiliary variables are fully defined by the original ones, every model of
+// the expression extends to exactly one model of the CNF formula.
+//
+// An error is returned if the expression contains a literal 0 or a variable
+// greater than NumVars (which would clash with the auxiliary variables).
+func (f SATFormula) ToCNF() (cnf CNFFormula, firstAux int, err error) {
+	if err := checkExpr(f.Expr, f.NumVars); err != nil {
+		return CNFFormula{}, 0, err
+	}
+	t := tseitin{nVars: f.NumVars}
+	if f.Expr != nil {
+		t.add(t.encode(f.Expr))
+	}
+	return CNFFormula{NumVars: t.nVars, Clauses: t.clauses}, f.NumVars + 1, nil
+}
+
+// checkExpr returns an error if e contains a literal 0 or a variable greater
+// than nVars.
+func checkExpr(e Expr, nVars int) error {
+	var xs []Expr
+	switch e := e.(type) {
+	case Lit:
+		if e == 0 {
+			return fmt.Errorf("invalid literal 0 in expression")
+		}
+		if abs(int(e)) > nVars {
+			return fmt.Errorf("%w: literal %d, expected variables in 1..%d", ErrVarOutOfRange, e, nVars)
+		}
+		return nil
+	case Not:
+		return checkExpr(e.X, nVars)
+	case And:
+		xs = e
+	case Or:
+		xs = e
+	case Xor:
+		xs = e
+	}
+	for _, x := range xs {
+		if err := checkExpr(x, nVars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tseitin holds the state of a Tseitin transformation.
+type tseitin struct {
+	nVars   int
+	clauses [][]int
+	top     int // literal that is always true, 0 if not yet allocated
+}
+
+func (t *tseitin) newVar() int {
+	t.nVars++
+	return t.nVars
+}
+
+func (t *tseitin) add(clause ...int) {
+	t.clauses = append(t.clauses, clause)
+}
+
+// constant returns a literal whose value is b.
+func (t *tseitin) constant(b bool) int {
+	if t.top == 0 {
+		t.top = t.newVar()
+		t.add(t.top)
+	}
+	if b {
+		return t.top
+	}
+	return -t.top
+}
+
+// encode returns a literal equivalent to e, adding the clauses that define
+// the auxiliary variables it needs.
+func (t *tseitin) encode(e Expr) int {
+	switch e := e.(type) {
+	case Lit:
+		return int(e)
+	case Not:
+		return -t.encode(e.X)
+	case And:
+		return t.encodeAndOr(e, true)
+	case Or:
+		return t.encodeAndOr(e, false)
+	case Xor:
+		if len(e) == 0 {
+			return t.constant(false)
+		}
+		x := t.encode(e[0])
+		for _, sub := range e[1:] {
+			l := t.encode(sub)
+			y := t.newVar() // y <-> x xor l
+			t.add(-y, x, l)
+			t.add(-y, -x, -l)
+			t.add(y, -x, l)
+			t.add(y, x, -l)
+			x = y
+		}
+		return x
+	}
+	return t.constant(true) // nil expression
+}
+
+// encodeAndOr encodes a conjunction if and is true and a disjunction
+// otherwise. Disjunctions are encoded as negated conjunctions of the negated
+// subexpressions.
+func (t *tseitin) encodeAndOr(xs []Expr, and bool) int {
+	sign := 1
+	if !and {
+		sign = -1
+	}
+	if len(xs) == 0 {
+		return t.constant(and)
+	}
+	if len(xs) == 1 {
+		return t.encode(xs[0])
+	}
+
+	lits := make([]int, len(xs))
+	for i, x := range xs {
+		lits[i] = sign * t.encode(x)
+	}
+	a := t.newVar() // a <-> lits[0] and ... and lits[n-1]
+	long := make([]int, 0, len(lits)+1)
+	long = append(long, a)
+	for _, l := range lits {
+		t.add(-a, l)
+		long = append(long, -l)
+	}
+	t.add(long...)
+	return sign * a
+}
+
+// TseitinNegate returns a CNF formula that is equisatisfiable with the
+// negation of f, which is useful to build equivalence checks (e.g. f and g are
+// equivalent if neither f and not g, nor g and not f, are satisfiable). An
+// auxiliary variable NumVars+i+1 is introduced for the i-th clause of f and
+// is true if and only if that clause is falsified, the returned formula
+// requiring at least one of them to be true. Its NumVars thus accounts for the
+// auxiliary variables. The negation of a formula without clauses is the
+// formula made of a single empty clause.
+//
+// An error is returned if a clause contains a 0 or a variable greater than
+// NumVars (which would clash with the auxiliary variables).
+func (f CNFFormula) TseitinNegate() (CNFFormula, error) {
+	for _, c := range f.Clauses {
+		for _, l := range c {
+			if l == 0 {
+				return CNFFormula{}, fmt.Errorf("invalid literal 0 in clause %v", c)
+			}
+			if abs(l) > f.NumVars {
+				return CNFFormula{}, fmt.Errorf("%w: literal %d, expected variables in 1..%d", ErrVarOutOfRange, l, f.NumVars)
+			}
+		}
+	}
+
+	t := tseitin{nVars: f.NumVars}
+	falsified := make([]int, len(f.Clauses))
+	for i, c := range f.Clauses {
+		a := t.newVar() // a <-> not c
+		def := make([]int, 0, len(c)+1)
+		def = append(def, a)
+		for _, l := range c {
+			t.add(-a, -l)
+			def = append(def, l)
+		}
+		t.add(def...)
+		falsified[i] = a
+	}
+	t.add(falsified...)
+	return CNFFormula{NumVars: t.nVars, Clauses: t.clauses}, nil
+}
@@ -0,0 +1,298 @@
+package dimacs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// evalExpr returns the value of e under the given assignment, where variable v
+// is true if assignment[v-1] is positive.
+func evalExpr(e Expr, assignment []int) bool {
+	switch e := e.(type) {
+	case Lit:
+		return (e > 0) == (assignment[abs(int(e))-1] > 0)
+	case Not:
+		return !evalExpr(e.X, assignment)
+	case And:
+		for _, x := range e {
+			if !evalExpr(x, assignment) {
+				return false
+			}
+		}
+		return true
+	case Or:
+		for _, x := range e {
+			if evalExpr(x, assignment) {
+				return true
+			}
+		}
+		return false
+	case Xor:
+		res := false
+		for _, x := range e {
+			res = res != evalExpr(x, assignment)
+		}
+		return res
+	}
+	return true
+}
+
+// assignments returns all the assignments of n variables.
+func assignments(n int) [][]int {
+	res := [][]int{}
+	for m := 0; m < 1<<n; m++ {
+		a := make([]int, n)
+		for v := 1; v <= n; v++ {
+			if m&(1<<(v-1)) != 0 {
+				a[v-1] = v
+			} else {
+				a[v-1] = -v
+			}
+		}
+		res = append(res, a)
+	}
+	return res
+}
+
+func TestToCNF(t *testing.T) {
+	testCases := []struct {
+		desc string
+		f    SATFormula
+	}{
+		{
+			desc: "nil expression",
+			f:    SATFormula{NumVars: 1},
+		},
+		{
+			desc: "literal",
+			f:    SATFormula{NumVars: 2, Expr: Lit(-2)},
+		},
+		{
+			desc: "empty and",
+			f:    SATFormula{NumVars: 1, Expr: And{}},
+		},
+		{
+			desc: "empty or",
+			f:    SATFormula{NumVars: 1, Expr: Or{}},
+		},
+		{
+			desc: "empty xor",
+			f:    SATFormula{NumVars: 1, Expr: Xor{}},
+		},
+		{
+			desc: "single child",
+			f:    SATFormula{NumVars: 2, Expr: Or{And{Lit(2)}}},
+		},
+		{
+			desc: "nested",
+			f: SATFormula{NumVars: 4, Expr: And{
+				Or{Lit(1), Not{And{Lit(2), Lit(-3)}}},
+				Not{Or{Lit(4), Lit(1)}},
+				Or{Lit(3), Lit(-2), Lit(4)},
+			}},
+		},
+		{
+			desc: "xor",
+			f:    SATFormula{NumVars: 3, Expr: Xor{Lit(1), Not{Lit(2)}, Or{Lit(3), Lit(1)}}},
+		},
+		{
+			desc: "unsatisfiable",
+			f:    SATFormula{NumVars: 2, Expr: And{Xor{Lit(1), Lit(2)}, Or{And{Lit(1), Lit(2)}, And{Lit(-1), Lit(-2)}}}},
+		},
+		{
+			desc: "constants",
+			f:    SATFormula{NumVars: 2, Expr: Or{And{}, Xor{Lit(1), Lit(2)}, Not{Or{}}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cnf, firstAux, err := tc.f.ToCNF()
+
+			if err != nil {
+				t.Fatalf("ToCNF(): want no error, got %s", err)
+			}
+			if firstAux != tc.f.NumVars+1 {
+				t.Errorf("ToCNF(): want first auxiliary variable %d, got %d", tc.f.NumVars+1, firstAux)
+			}
+			if cnf.NumVars < tc.f.NumVars {
+				t.Fatalf("ToCNF(): want at least %d variables, got %d", tc.f.NumVars, cnf.NumVars)
+			}
+
+			// Count the models of the CNF formula that extend each assignment of
+			// the original variables.
+			models := map[string]int{}
+			for _, a := range assignments(cnf.NumVars) {
+				ok, err := cnf.Satisfies(a)
+				if err != nil {
+					t.Fatalf("Satisfies(): want no error, got %s", err)
+				}
+				if ok {
+					models[fmt.Sprint(a[:tc.f.NumVars])]++
+				}
+			}
+			for _, a := range assignments(tc.f.NumVars) {
+				want := 0
+				if evalExpr(tc.f.Expr, a) {
+					want = 1
+				}
+				if got := models[fmt.Sprint(a)]; got != want {
+					t.Errorf("ToCNF(): assignment %v: want %d models, got %d", a, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestToCNF_auxiliaryRange(t *testing.T) {
+	f := SATFormula{NumVars: 2, Expr: Or{Lit(1), And{Lit(-1), Lit(2)}}}
+
+	cnf, firstAux, err := f.ToCNF()
+
+	if err != nil {
+		t.Fatalf("ToCNF(): want no error, got %s", err)
+	}
+	if firstAux != 3 || cnf.NumVars != 4 {
+		t.Errorf("ToCNF(): want auxiliary variables 3..4, got %d..%d", firstAux, cnf.NumVars)
+	}
+	for _, v := range cnf.UsedVariables() {
+		if v > cnf.NumVars {
+			t.Errorf("ToCNF(): variable %d out of range 1..%d", v, cnf.NumVars)
+		}
+	}
+}
+
+func TestToCNF_invalidLiterals(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		f      SATFormula
+		wantIs error // sentinel error wrapped by the error, if any
+	}{
+		{
+			desc: "zero literal",
+			f:    SATFormula{NumVars: 2, Expr: Or{Lit(1), Lit(0)}},
+		},
+		{
+			desc:   "variable out of range",
+			f:      SATFormula{NumVars: 2, Expr: And{Lit(1), Not{Lit(-3)}}},
+			wantIs: ErrVarOutOfRange,
+		},
+		{
+			desc:   "variable out of range in xor",
+			f:      SATFormula{NumVars: 2, Expr: Xor{Lit(1), Lit(3)}},
+			wantIs: ErrVarOutOfRange,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, _, gotErr := tc.f.ToCNF()
+
+			if gotErr == nil {
+				t.Fatalf("ToCNF(): want error, got nil")
+			}
+			if tc.wantIs != nil && !errors.Is(gotErr, tc.wantIs) {
+				t.Errorf("ToCNF(): want error %q, got %v", tc.wantIs, gotErr)
+			}
+		})
+	}
+}
+
+func TestTseitinNegate(t *testing.T) {
+	testCases := []struct {
+		desc string
+		f    CNFFormula
+	}{
+		{
+			desc: "no clauses",
+			f:    CNFFormula{NumVars: 2, Clauses: [][]int{}},
+		},
+		{
+			desc: "empty clause",
+			f:    CNFFormula{NumVars: 1, Clauses: [][]int{{1}, {}}},
+		},
+		{
+			desc: "satisfiable",
+			f:    testFormula,
+		},
+		{
+			desc: "valid",
+			f:    CNFFormula{NumVars: 2, Clauses: [][]int{{1, -1}, {2, -2, 1}}},
+		},
+		{
+			desc: "unsatisfiable",
+			f:    CNFFormula{NumVars: 2, Clauses: [][]int{{1, 2}, {-1, 2}, {1, -2}, {-1, -2}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			neg, err := tc.f.TseitinNegate()
+			if err != nil {
+				t.Fatalf("TseitinNegate(): want no error, got %s", err)
+			}
+
+			if want := tc.f.NumVars + len(tc.f.Clauses); neg.NumVars != want {
+				t.Errorf("TseitinNegate(): want %d variables, got %d", want, neg.NumVars)
+			}
+
+			// Count the models of the negation that extend each assignment of
+			// the original variables.
+			models := map[string]int{}
+			for _, a := range assignments(neg.NumVars) {
+				ok, err := neg.Satisfies(a)
+				if err != nil {
+					t.Fatalf("Satisfies(): want no error, got %s", err)
+				}
+				if ok {
+					models[fmt.Sprint(a[:tc.f.NumVars])]++
+				}
+			}
+			for _, a := range assignments(tc.f.NumVars) {
+				sat, err := tc.f.Satisfies(a)
+				if err != nil {
+					t.Fatalf("Satisfies(): want no error, got %s", err)
+				}
+				want := 1
+				if sat {
+					want = 0
+				}
+				if got := models[fmt.Sprint(a)]; got != want {
+					t.Errorf("TseitinNegate(): assignment %v: want %d models, got %d", a, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestTseitinNegate_invalidClauses(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		f      CNFFormula
+		wantIs error // sentinel error wrapped by the error, if any
+	}{
+		{
+			desc: "zero literal",
+			f:    CNFFormula{NumVars: 2, Clauses: [][]int{{1, 0}}},
+		},
+		{
+			desc:   "variable out of range",
+			f:      CNFFormula{NumVars: 2, Clauses: [][]int{{1, -3}}},
+			wantIs: ErrVarOutOfRange,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, gotErr := tc.f.TseitinNegate()
+
+			if gotErr == nil {
+				t.Fatalf("TseitinNegate(): want error, got nil")
+			}
+			if tc.wantIs != nil && !errors.Is(gotErr, tc.wantIs) {
+				t.Errorf("TseitinNegate(): want error %q, got %v", tc.wantIs, gotErr)
+			}
+		})
+	}
+}
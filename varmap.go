@@ -0,0 +1,107 @@
+package dimacs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// varMapHeader is the comment line written at the beginning of variable maps.
+const varMapHeader = "c variable map: <old> <new>"
+
+// WriteVarMap writes the given variable mapping (e.g. as returned by
+// CNFFormula.Compact) to w so that it can be read back with ReadVarMap. The
+// format is a comment line followed by one "<old> <new>" line per variable,
+// sorted by old variable:
+//
+//	c variable map: <old> <new>
+//	1 1
+//	5 2
+//	9 3
+//
+// An error is returned if a variable of the mapping is not positive.
+func WriteVarMap(w io.Writer, mapping map[int]int) error {
+	olds := make([]int, 0, len(mapping))
+	for old, v := range mapping {
+		if old <= 0 || v <= 0 {
+			return fmt.Errorf("invalid mapping %d -> %d: variables must be positive", old, v)
+		}
+		olds = append(olds, old)
+	}
+	sort.Ints(olds)
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(varMapHeader)
+	bw.WriteByte('\n')
+	buf := make([]byte, 0, 32)
+	for _, old := range olds {
+		buf = strconv.AppendInt(buf[:0], int64(old), 10)
+		buf = append(buf, ' ')
+		buf = strconv.AppendInt(buf, int64(mapping[old]), 10)
+		buf = append(buf, '\n')
+		bw.Write(buf)
+	}
+	return bw.Flush()
+}
+
+// ReadVarMap reads a variable mapping written by WriteVarMap. Comment lines
+// (starting with "c") and empty lines are ignored. An error is returned if a
+// line is not made of two positive variables, or if a variable is mapped more
+// than once or two variables are mapped to the same one, as the mapping could
+// then not be inverted.
+func ReadVarMap(r io.Reader) (map[int]int, error) {
+	rd := Reader{}
+	rd.Reset(r)
+	mapping := map[int]int{}
+	seen := map[int]struct{}{} // new variables
+
+	for n := 1; ; n++ {
+		b, err := rd.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n == 1 {
+			b = bytes.TrimPrefix(b, []byte(utf8BOM))
+		}
+		line := strings.TrimSpace(string(b))
+		if line == "" || line[0] == 'c' {
+			continue
+		}
+
+		old, v, err := parseVarMapLine(line)
+		if err == nil {
+			if _, ok := mapping[old]; ok {
+				err = fmt.Errorf("variable %d mapped more than once", old)
+			} else if _, ok := seen[v]; ok {
+				err = fmt.Errorf("several variables mapped to %d", v)
+			}
+		}
+		if err != nil {
+			return nil, newParseError(n, line, err)
+		}
+		mapping[old] = v
+		seen[v] = struct{}{}
+	}
+	return mapping, nil
+}
+
+// parseVarMapLine parses a "<old> <new>" line.
+func parseVarMapLine(line string) (int, int, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("invalid variable map line %q", line)
+	}
+	old, err1 := strconv.Atoi(fields[0])
+	v, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil || old <= 0 || v <= 0 {
+		return 0, 0, fmt.Errorf("invalid variable map line %q", line)
+	}
+	return old, v, nil
+}
@@ -0,0 +1,160 @@
+package dimacs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteVarMap(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		mapping map[int]int
+		want    string
+		wantErr bool
+	}{
+		{
+			desc:    "empty mapping",
+			mapping: map[int]int{},
+			want:    "c variable map: <old> <new>\n",
+			wantErr: false,
+		},
+		{
+			desc:    "sorted by old variable",
+			mapping: map[int]int{9: 3, 1: 1, 5: 2},
+			want:    "c variable map: <old> <new>\n1 1\n5 2\n9 3\n",
+			wantErr: false,
+		},
+		{
+			desc:    "non-positive variable",
+			mapping: map[int]int{1: 1, -2: 2},
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+
+			gotErr := WriteVarMap(buf, tc.mapping)
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("WriteVarMap(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("WriteVarMap(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.want, buf.String()); diff != "" {
+				t.Errorf("WriteVarMap(): output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadVarMap(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		want    map[int]int
+		wantErr bool
+	}{
+		{
+			desc:    "byte order mark",
+			input:   "\ufeff5 1\n",
+			want:    map[int]int{5: 1},
+			wantErr: false,
+		},
+		{
+			desc:    "empty input",
+			input:   "",
+			want:    map[int]int{},
+			wantErr: false,
+		},
+		{
+			desc:    "valid mapping",
+			input:   "c variable map: <old> <new>\n1 1\n\n5  2\nc note\n9\t3\n",
+			want:    map[int]int{1: 1, 5: 2, 9: 3},
+			wantErr: false,
+		},
+		{
+			desc:    "missing column",
+			input:   "1 1\n5\n",
+			wantErr: true,
+		},
+		{
+			desc:    "extra column",
+			input:   "1 1 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid variable",
+			input:   "1 x\n",
+			wantErr: true,
+		},
+		{
+			desc:    "non-positive variable",
+			input:   "0 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "variable mapped twice",
+			input:   "1 1\n1 2\n",
+			wantErr: true,
+		},
+		{
+			desc:    "not invertible",
+			input:   "1 1\n2 1\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotErr := ReadVarMap(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadVarMap(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadVarMap(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ReadVarMap(): mapping mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVarMap_roundTrip(t *testing.T) {
+	f := CNFFormula{NumVars: 12, Clauses: [][]int{{7, -3}, {-7, 12, 3}, {-12}}}
+	want := f.Compact()
+	buf := &bytes.Buffer{}
+
+	if err := WriteVarMap(buf, want); err != nil {
+		t.Fatalf("WriteVarMap(): want no error, got %s", err)
+	}
+	got, err := ReadVarMap(buf)
+
+	if err != nil {
+		t.Fatalf("ReadVarMap(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ReadVarMap(): mapping mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadVarMap_longLine(t *testing.T) {
+	input := "c " + strings.Repeat("x", 100000) + "\n5 1\n"
+
+	got, err := ReadVarMap(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadVarMap(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff(map[int]int{5: 1}, got); diff != "" {
+		t.Errorf("ReadVarMap(): mapping mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,318 @@
+package dimacs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteCNF writes the given formula to w in the DIMACS CNF format, that is
+// a problem line followed by one line per clause terminated by a 0.
+func WriteCNF(w io.Writer, f CNFFormula) error {
+	bw := bufio.NewWriter(w)
+	if err := writeCNF(bw, f, -1); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// WriteTo writes the formula to w as WriteCNF does and returns the number of
+// bytes written. It implements io.WriterTo.
+func (f CNFFormula) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := WriteCNF(cw, f)
+	return cw.n, err
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// WriteCNFWithComments is like WriteCNF but first writes each of the given
+// comments as a "c <comment>" line before the problem line. An error is
+// returned, and nothing is written, if a comment contains a line break.
+func WriteCNFWithComments(w io.Writer, f CNFFormula, comments []string) error {
+	for _, c := range comments {
+		if strings.ContainsAny(c, "\r\n") {
+			return fmt.Errorf("comment contains a line break: %q", c)
+		}
+	}
+	cw := NewWriter(w)
+	for _, c := range comments {
+		if err := cw.WriteComment(c); err != nil {
+			return err
+		}
+	}
+	if err := writeCNF(cw.bw, f, -1); err != nil {
+		return err
+	}
+	return cw.Flush()
+}
+
+// WriteCNFWrapped is like WriteCNF but wraps the clauses so that their lines
+// are at most maxCols bytes long (excluding the line break). A wrapped clause
+// spans several lines and only the last one holds the terminating 0. Literals
+// are never split: a literal longer than maxCols is written on its own line.
+// Clauses are not wrapped if maxCols is not positive.
+//
+// Wrapped clauses are valid DIMACS but are read as several clauses unless
+// ReadOptions.MultiLineClauses (or ReadCNFOptions.MultiLineClauses) is set.
+func WriteCNFWrapped(w io.Writer, f CNFFormula, maxCols int) error {
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, 0, 64)
+	buf = appendProblem(buf, "cnf", f.NumVars, len(f.Clauses))
+	if _, err := bw.Write(buf); err != nil {
+		return err
+	}
+	for _, c := range f.Clauses {
+		buf = appendWrappedClause(buf[:0], c, maxCols)
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// appendWrappedClause is like appendClause but starts a new line whenever the
+// next token would make the current one longer than maxCols.
+func appendWrappedClause(buf []byte, clause []int, maxCols int) []byte {
+	if maxCols <= 0 {
+		return appendClause(buf, clause)
+	}
+	lineStart := len(buf)
+	for i := 0; i <= len(clause); i++ {
+		tokStart := len(buf)
+		if tokStart > lineStart {
+			buf = append(buf, ' ')
+		}
+		if i < len(clause) {
+			buf = strconv.AppendInt(buf, int64(clause[i]), 10)
+		} else {
+			buf = append(buf, '0')
+		}
+		if len(buf)-lineStart > maxCols && tokStart > lineStart {
+			buf[tokStart] = '\n' // replace the space
+			lineStart = tokStart + 1
+		}
+	}
+	return append(buf, '\n')
+}
+
+// StringMaxClauses is the maximum number of clauses written by
+// CNFFormula.String. Remaining clauses are summarized by a single trailing
+// line of the form "... (N more clauses)". A negative value disables the
+// truncation.
+var StringMaxClauses = 1000
+
+// String returns the DIMACS CNF representation of the formula as written by
+// WriteCNF. The output is truncated after StringMaxClauses clauses and is thus
+// only re-parseable for small formulas. It is meant for debugging and logging;
+// use WriteCNF to serialize formulas.
+func (f CNFFormula) String() string {
+	sb := strings.Builder{}
+	writeCNF(&sb, f, StringMaxClauses) // writing to a strings.Builder never fails
+	return sb.String()
+}
+
+// WriteXorCNF is like WriteCNF but also writes the xor clauses of f, as "x"
+// lines following the regular clauses (see XorBuilder). The number of clauses
+// of the problem line counts both regular and xor clauses, as expected by
+// ReadXorCNF.
+func WriteXorCNF(w io.Writer, f XorCNFFormula) error {
+	bw := bufio.NewWriter(w)
+	if err := writeXorCNF(bw, f, -1); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// WriteTo writes the formula to w as WriteXorCNF does and returns the number
+// of bytes written. It implements io.WriterTo.
+func (f XorCNFFormula) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := WriteXorCNF(cw, f)
+	return cw.n, err
+}
+
+// String returns the representation of the formula as written by WriteXorCNF,
+// truncated as in CNFFormula.String.
+func (f XorCNFFormula) String() string {
+	sb := strings.Builder{}
+	writeXorCNF(&sb, f, StringMaxClauses) // writing to a strings.Builder never fails
+	return sb.String()
+}
+
+// writeCNF writes the problem line of f followed by at most maxClauses of its
+// clauses (all of them if maxClauses is negative).
+func writeCNF(w io.Writer, f CNFFormula, maxClauses int) error {
+	return writeXorCNF(w, XorCNFFormula{CNFFormula: f}, maxClauses)
+}
+
+// writeXorCNF is like writeCNF but also writes the xor clauses of f, which
+// count towards maxClauses.
+func writeXorCNF(w io.Writer, f XorCNFFormula, maxClauses int) error {
+	nClauses := len(f.Clauses) + len(f.XorClauses)
+	buf := make([]byte, 0, 64)
+	buf = appendProblem(buf, "cnf", f.NumVars, nClauses)
+	if _, err := w.Write(buf); err != nil {
+		return err
+	}
+	for i := 0; i < nClauses; i++ {
+		if i == maxClauses {
+			buf = append(buf[:0], "... ("...)
+			buf = strconv.AppendInt(buf, int64(nClauses-i), 10)
+			buf = append(buf, " more clauses)\n"...)
+			_, err := w.Write(buf)
+			return err
+		}
+		if i < len(f.Clauses) {
+			buf = appendClause(buf[:0], f.Clauses[i])
+		} else {
+			buf = append(buf[:0], 'x')
+			buf = appendClause(buf, f.XorClauses[i-len(f.Clauses)])
+		}
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendProblem appends a problem line to buf and returns the extended buffer.
+func appendProblem(buf []byte, problem string, nVars int, nClauses int) []byte {
+	buf = append(buf, "p "...)
+	buf = append(buf, problem...)
+	buf = append(buf, ' ')
+	buf = strconv.AppendInt(buf, int64(nVars), 10)
+	buf = append(buf, ' ')
+	buf = strconv.AppendInt(buf, int64(nClauses), 10)
+	return append(buf, '\n')
+}
+
+// appendClause appends a clause line, including its terminating 0, to buf and
+// returns the extended buffer.
+func appendClause(buf []byte, clause []int) []byte {
+	for _, l := range clause {
+		buf = strconv.AppendInt(buf, int64(l), 10)
+		buf = append(buf, ' ')
+	}
+	return append(buf, "0\n"...)
+}
+
+// Writer writes DIMACS CNF files line by line, which avoids building the whole
+// formula in memory. Lines are buffered and Flush must be called once all the
+// lines have been written.
+//
+// Clauses are validated before being written so that invalid clauses are
+// reported rather than silently producing an invalid file (see WriteClause).
+type Writer struct {
+	// RejectDuplicateLiterals makes WriteClause reject the clauses in which
+	// a literal appears more than once.
+	RejectDuplicateLiterals bool
+
+	bw    *bufio.Writer
+	buf   []byte
+	nVars int // declared number of variables, -1 if unknown
+}
+
+// NewWriter returns a new Writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{bw: bufio.NewWriter(w), nVars: -1}
+}
+
+// WriteProblem writes the problem line "p <problem> <nVars> <nClauses>". The
+// clauses written afterwards must only contain variables in 1..nVars.
+func (w *Writer) WriteProblem(problem string, nVars int, nClauses int) error {
+	w.buf = appendProblem(w.buf[:0], problem, nVars, nClauses)
+	if _, err := w.bw.Write(w.buf); err != nil {
+		return err
+	}
+	w.nVars = nVars
+	return nil
+}
+
+// WriteClause writes a clause line terminated by a 0. Nothing is written and
+// an error is returned if the clause contains a 0 literal, a variable greater
+// than the number of variables declared with WriteProblem (if called), or a
+// repeated literal if RejectDuplicateLiterals is set. The last two errors
+// wrap ErrVarOutOfRange and ErrDuplicateLiteral respectively.
+func (w *Writer) WriteClause(clause []int) error {
+	for _, l := range clause {
+		if l == 0 {
+			return fmt.Errorf("invalid literal 0 in clause %v", clause)
+		}
+		if w.nVars >= 0 && abs(l) > w.nVars {
+			return fmt.Errorf("%w: literal %d, expected variables in 1..%d", ErrVarOutOfRange, l, w.nVars)
+		}
+	}
+	if w.RejectDuplicateLiterals {
+		if l, ok := duplicateLiteral(clause); ok {
+			return fmt.Errorf("%w: literal %d appears more than once", ErrDuplicateLiteral, l)
+		}
+	}
+	w.buf = appendClause(w.buf[:0], clause)
+	_, err := w.bw.Write(w.buf)
+	return err
+}
+
+// WriteComment writes the comment line "c <text>". The text cannot contain
+// line breaks.
+func (w *Writer) WriteComment(text string) error {
+	if strings.ContainsAny(text, "\r\n") {
+		return fmt.Errorf("comment contains a line break: %q", text)
+	}
+	w.buf = append(w.buf[:0], 'c')
+	if text != "" {
+		w.buf = append(w.buf, ' ')
+		w.buf = append(w.buf, text...)
+	}
+	w.buf = append(w.buf, '\n')
+	_, err := w.bw.Write(w.buf)
+	return err
+}
+
+// WriteMeta writes a comment line of the form "c <key>: <value>" which can be
+// parsed back with ParseMeta (e.g. to record the generator of a formula). The
+// key must be non-empty and cannot contain white spaces or colons, and the
+// value cannot contain line breaks.
+func (w *Writer) WriteMeta(key string, value string) error {
+	if !isMetaKey(key) {
+		return fmt.Errorf("invalid metadata key %q", key)
+	}
+	return w.WriteComment(key + ": " + value)
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.bw.Flush()
+}
+
+// ParseMeta parses a comment line written by Writer.WriteMeta, such as the
+// lines passed to Builder.Comment, and returns its key and value. Surrounding
+// white spaces are trimmed from the value. It returns false if the comment is
+// not of the form "c <key>: <value>".
+func ParseMeta(comment string) (key string, value string, ok bool) {
+	if !strings.HasPrefix(comment, "c ") {
+		return "", "", false
+	}
+	key, value, ok = strings.Cut(strings.TrimSpace(comment[2:]), ":")
+	if !ok || !isMetaKey(key) {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(value), true
+}
+
+// isMetaKey returns true if key is a valid metadata key.
+func isMetaKey(key string) bool {
+	return key != "" && !strings.ContainsAny(key, ": \t\r\n\v\f")
+}
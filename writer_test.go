@@ -0,0 +1,605 @@
+package dimacs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var testFormula = CNFFormula{
+	NumVars: 3,
+	Clauses: [][]int{
+		{1, 2, 3},
+		{1, -2, 3},
+		{1, -3},
+		{-2, -3},
+	},
+}
+
+const testFormulaDIMACS = `p cnf 3 4
+1 2 3 0
+1 -2 3 0
+1 -3 0
+-2 -3 0
+`
+
+func TestWriteCNF(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	if err := WriteCNF(buf, testFormula); err != nil {
+		t.Fatalf("WriteCNF(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff(testFormulaDIMACS, buf.String()); diff != "" {
+		t.Errorf("WriteCNF(): output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteCNF_roundTrip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := WriteCNF(buf, testFormula); err != nil {
+		t.Fatalf("WriteCNF(): want no error, got %s", err)
+	}
+
+	got, err := ReadCNF(buf)
+	if err != nil {
+		t.Fatalf("ReadCNF(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff(testFormula, got); diff != "" {
+		t.Errorf("round trip: CNF mismatch (-want +got):\n%s", diff)
+	}
+}
+
+type errWriter struct{ err error }
+
+func (w errWriter) Write(_ []byte) (int, error) { return 0, w.err }
+
+func TestWriteCNF_writerError(t *testing.T) {
+	wantErr := errors.New("test error")
+
+	gotErr := WriteCNF(errWriter{wantErr}, testFormula)
+
+	if !errors.Is(gotErr, wantErr) {
+		t.Errorf("WriteCNF(): want error %s, got %v", wantErr, gotErr)
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var _ io.WriterTo = testFormula
+
+	n, err := testFormula.WriteTo(buf)
+
+	if err != nil {
+		t.Fatalf("WriteTo(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(testFormulaDIMACS, buf.String()); diff != "" {
+		t.Errorf("WriteTo(): output mismatch (-want +got):\n%s", diff)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo(): want %d bytes written, got %d", buf.Len(), n)
+	}
+}
+
+// shortWriter accepts the first max bytes and then fails.
+type shortWriter struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if room := w.max - w.buf.Len(); len(p) > room {
+		w.buf.Write(p[:room])
+		return room, errors.New("short write")
+	}
+	return w.buf.Write(p)
+}
+
+func TestWriteTo_writerError(t *testing.T) {
+	w := &shortWriter{max: 12}
+
+	n, err := testFormula.WriteTo(w)
+
+	if err == nil {
+		t.Errorf("WriteTo(): want error, got nil")
+	}
+	if n != 12 || w.buf.Len() != 12 {
+		t.Errorf("WriteTo(): want 12 bytes written, got %d (%d in writer)", n, w.buf.Len())
+	}
+}
+
+func TestWriteCNFWithComments(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		comments []string
+		want     string
+		wantErr  bool
+	}{
+		{
+			desc:     "no comments",
+			comments: nil,
+			want:     testFormulaDIMACS,
+			wantErr:  false,
+		},
+		{
+			desc:     "comments",
+			comments: []string{"generated by test", "", "seed: 42"},
+			want:     "c generated by test\nc\nc seed: 42\n" + testFormulaDIMACS,
+			wantErr:  false,
+		},
+		{
+			desc:     "line break",
+			comments: []string{"first", "two\nlines"},
+			want:     "",
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+
+			gotErr := WriteCNFWithComments(buf, testFormula, tc.comments)
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("WriteCNFWithComments(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("WriteCNFWithComments(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.want, buf.String()); diff != "" {
+				t.Errorf("WriteCNFWithComments(): output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteCNFWrapped(t *testing.T) {
+	f := CNFFormula{
+		NumVars: 12,
+		Clauses: [][]int{
+			{1, -2, 3, -4, 5, 6},
+			{-12},
+			{},
+			{10, 11, -12, 1},
+		},
+	}
+	testCases := []struct {
+		desc    string
+		maxCols int
+		want    string
+	}{
+		{
+			desc:    "no wrapping",
+			maxCols: 0,
+			want:    "p cnf 12 4\n1 -2 3 -4 5 6 0\n-12 0\n0\n10 11 -12 1 0\n",
+		},
+		{
+			desc:    "large limit",
+			maxCols: 80,
+			want:    "p cnf 12 4\n1 -2 3 -4 5 6 0\n-12 0\n0\n10 11 -12 1 0\n",
+		},
+		{
+			desc:    "exact fit",
+			maxCols: 15,
+			want:    "p cnf 12 4\n1 -2 3 -4 5 6 0\n-12 0\n0\n10 11 -12 1 0\n",
+		},
+		{
+			desc:    "wrapped",
+			maxCols: 8,
+			want:    "p cnf 12 4\n1 -2 3\n-4 5 6 0\n-12 0\n0\n10 11\n-12 1 0\n",
+		},
+		{
+			desc:    "literals longer than limit",
+			maxCols: 1,
+			want:    "p cnf 12 4\n1\n-2\n3\n-4\n5\n6\n0\n-12\n0\n0\n10\n11\n-12\n1\n0\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+
+			if err := WriteCNFWrapped(buf, f, tc.maxCols); err != nil {
+				t.Fatalf("WriteCNFWrapped(): want no error, got %s", err)
+			}
+
+			if diff := cmp.Diff(tc.want, buf.String()); diff != "" {
+				t.Errorf("WriteCNFWrapped(): output mismatch (-want +got):\n%s", diff)
+			}
+
+			got, err := ReadCNFWithOptions(buf, ReadCNFOptions{MultiLineClauses: true})
+			if err != nil {
+				t.Fatalf("ReadCNFWithOptions(): want no error, got %s", err)
+			}
+			if diff := cmp.Diff(f, got); diff != "" {
+				t.Errorf("round trip: CNF mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteCNFWrapped_writerError(t *testing.T) {
+	wantErr := errors.New("test error")
+
+	gotErr := WriteCNFWrapped(errWriter{wantErr}, testFormula, 4)
+
+	if !errors.Is(gotErr, wantErr) {
+		t.Errorf("WriteCNFWrapped(): want error %s, got %v", wantErr, gotErr)
+	}
+}
+
+func TestString(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		maxClauses int
+		want       string
+	}{
+		{
+			desc:       "no truncation",
+			maxClauses: -1,
+			want:       testFormulaDIMACS,
+		},
+		{
+			desc:       "large limit",
+			maxClauses: 10,
+			want:       testFormulaDIMACS,
+		},
+		{
+			desc:       "truncated",
+			maxClauses: 2,
+			want:       "p cnf 3 4\n1 2 3 0\n1 -2 3 0\n... (2 more clauses)\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			defer func(old int) { StringMaxClauses = old }(StringMaxClauses)
+			StringMaxClauses = tc.maxClauses
+
+			got := testFormula.String()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("String(): output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestString_roundTrip(t *testing.T) {
+	s := fmt.Sprint(testFormula)
+
+	got, err := ReadCNF(strings.NewReader(s))
+
+	if err != nil {
+		t.Fatalf("ReadCNF(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(testFormula, got); diff != "" {
+		t.Errorf("round trip: CNF mismatch (-want +got):\n%s", diff)
+	}
+}
+
+var testXorFormula = XorCNFFormula{
+	CNFFormula: CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}}},
+	XorClauses: [][]int{{1, 2, -3}, {2, 3}},
+}
+
+const testXorFormulaDIMACS = `p cnf 3 3
+1 -2 0
+x1 2 -3 0
+x2 3 0
+`
+
+func TestWriteXorCNF(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	if err := WriteXorCNF(buf, testXorFormula); err != nil {
+		t.Fatalf("WriteXorCNF(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff(testXorFormulaDIMACS, buf.String()); diff != "" {
+		t.Errorf("WriteXorCNF(): output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteXorCNF_roundTrip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := WriteXorCNF(buf, testXorFormula); err != nil {
+		t.Fatalf("WriteXorCNF(): want no error, got %s", err)
+	}
+
+	got, err := ReadXorCNF(buf)
+	if err != nil {
+		t.Fatalf("ReadXorCNF(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff(testXorFormula, got); diff != "" {
+		t.Errorf("round trip: formula mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestXorCNFFormula_WriteTo(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var _ io.WriterTo = testXorFormula
+
+	n, err := testXorFormula.WriteTo(buf)
+
+	if err != nil {
+		t.Fatalf("WriteTo(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(testXorFormulaDIMACS, buf.String()); diff != "" {
+		t.Errorf("WriteTo(): output mismatch (-want +got):\n%s", diff)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo(): want %d bytes written, got %d", buf.Len(), n)
+	}
+}
+
+func TestXorCNFFormula_String(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		maxClauses int
+		want       string
+	}{
+		{
+			desc:       "no truncation",
+			maxClauses: -1,
+			want:       testXorFormulaDIMACS,
+		},
+		{
+			desc:       "truncated in xor clauses",
+			maxClauses: 2,
+			want:       "p cnf 3 3\n1 -2 0\nx1 2 -3 0\n... (1 more clauses)\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			defer func(old int) { StringMaxClauses = old }(StringMaxClauses)
+			StringMaxClauses = tc.maxClauses
+
+			got := testXorFormula.String()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("String(): output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf)
+
+	if err := w.WriteMeta("generator", "test 1.0"); err != nil {
+		t.Fatalf("WriteMeta(): want no error, got %s", err)
+	}
+	if err := w.WriteComment(""); err != nil {
+		t.Fatalf("WriteComment(): want no error, got %s", err)
+	}
+	if err := w.WriteProblem("cnf", 3, 2); err != nil {
+		t.Fatalf("WriteProblem(): want no error, got %s", err)
+	}
+	if err := w.WriteComment("first clause"); err != nil {
+		t.Fatalf("WriteComment(): want no error, got %s", err)
+	}
+	if err := w.WriteClause([]int{1, -3}); err != nil {
+		t.Fatalf("WriteClause(): want no error, got %s", err)
+	}
+	if err := w.WriteClause([]int{}); err != nil {
+		t.Fatalf("WriteClause(): want no error, got %s", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush(): want no error, got %s", err)
+	}
+
+	want := "c generator: test 1.0\nc\np cnf 3 2\nc first clause\n1 -3 0\n0\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Writer: output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriter_invalidComments(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf)
+
+	if err := w.WriteComment("two\nlines"); err == nil {
+		t.Errorf("WriteComment(): want error, got nil")
+	}
+	if err := w.WriteMeta("two words", "value"); err == nil {
+		t.Errorf("WriteMeta(): want error, got nil")
+	}
+	if err := w.WriteMeta("", "value"); err == nil {
+		t.Errorf("WriteMeta(): want error, got nil")
+	}
+	if err := w.WriteMeta("key", "two\nlines"); err == nil {
+		t.Errorf("WriteMeta(): want error, got nil")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush(): want no error, got %s", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("Writer: want no output, got %q", buf.String())
+	}
+}
+
+func TestWriter_invalidClauses(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		noProblem bool
+		rejectDup bool
+		clause    []int
+		wantErr   bool
+		wantIs    error // sentinel error wrapped by the error, if any
+	}{
+		{
+			desc:    "valid clause",
+			clause:  []int{1, -3, 2},
+			wantErr: false,
+		},
+		{
+			desc:    "zero literal",
+			clause:  []int{1, 0, 2},
+			wantErr: true,
+		},
+		{
+			desc:    "variable out of range",
+			clause:  []int{1, -4},
+			wantErr: true,
+			wantIs:  ErrVarOutOfRange,
+		},
+		{
+			desc:      "no problem line",
+			noProblem: true,
+			clause:    []int{1, -4},
+			wantErr:   false,
+		},
+		{
+			desc:    "duplicate literals allowed",
+			clause:  []int{1, 2, 1},
+			wantErr: false,
+		},
+		{
+			desc:      "duplicate literals rejected",
+			rejectDup: true,
+			clause:    []int{1, 2, 1},
+			wantErr:   true,
+			wantIs:    ErrDuplicateLiteral,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w := NewWriter(buf)
+			w.RejectDuplicateLiterals = tc.rejectDup
+			if !tc.noProblem {
+				if err := w.WriteProblem("cnf", 3, 1); err != nil {
+					t.Fatalf("WriteProblem(): want no error, got %s", err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush(): want no error, got %s", err)
+			}
+			before := buf.Len()
+
+			gotErr := w.WriteClause(tc.clause)
+
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush(): want no error, got %s", err)
+			}
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("WriteClause(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("WriteClause(): want no error, got %s", gotErr)
+			}
+			if tc.wantIs != nil && !errors.Is(gotErr, tc.wantIs) {
+				t.Errorf("WriteClause(): want error %q, got %v", tc.wantIs, gotErr)
+			}
+			if gotErr != nil && buf.Len() != before {
+				t.Errorf("WriteClause(): want no output, got %q", buf.String()[before:])
+			}
+		})
+	}
+}
+
+func TestParseMeta(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		comment   string
+		wantKey   string
+		wantValue string
+		wantOK    bool
+	}{
+		{
+			desc:      "metadata",
+			comment:   "c seed: 42",
+			wantKey:   "seed",
+			wantValue: "42",
+			wantOK:    true,
+		},
+		{
+			desc:      "extra spaces",
+			comment:   "c   generator:   my tool: v2  ",
+			wantKey:   "generator",
+			wantValue: "my tool: v2",
+			wantOK:    true,
+		},
+		{
+			desc:      "empty value",
+			comment:   "c note:",
+			wantKey:   "note",
+			wantValue: "",
+			wantOK:    true,
+		},
+		{
+			desc:    "plain comment",
+			comment: "c just a comment",
+			wantOK:  false,
+		},
+		{
+			desc:    "key with spaces",
+			comment: "c generated by: me",
+			wantOK:  false,
+		},
+		{
+			desc:    "missing key",
+			comment: "c : value",
+			wantOK:  false,
+		},
+		{
+			desc:    "not a comment",
+			comment: "seed: 42",
+			wantOK:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotKey, gotValue, gotOK := ParseMeta(tc.comment)
+
+			if gotOK != tc.wantOK {
+				t.Errorf("ParseMeta(%q): want ok %t, got %t", tc.comment, tc.wantOK, gotOK)
+			}
+			if gotKey != tc.wantKey || gotValue != tc.wantValue {
+				t.Errorf("ParseMeta(%q): want (%q, %q), got (%q, %q)", tc.comment, tc.wantKey, tc.wantValue, gotKey, gotValue)
+			}
+		})
+	}
+}
+
+func TestParseMeta_roundTrip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf)
+	w.WriteMeta("generator", "gen v1.2")
+	w.WriteMeta("seed", "1234")
+	w.WriteProblem("cnf", 1, 1)
+	w.WriteClause([]int{1})
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush(): want no error, got %s", err)
+	}
+
+	cr := &commentRecorder{}
+	if err := ReadBuilder(buf, cr); err != nil {
+		t.Fatalf("ReadBuilder(): want no error, got %s", err)
+	}
+
+	got := map[string]string{}
+	for _, c := range cr.comments {
+		if k, v, ok := ParseMeta(c); ok {
+			got[k] = v
+		}
+	}
+	want := map[string]string{"generator": "gen v1.2", "seed": "1234"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ParseMeta(): metadata mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,99 @@
+package dimacs
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// XorBuilder extends Builder to process the xor clauses used by some SAT
+// solvers (e.g. CryptoMiniSat). Xor clause lines start with "x" followed by
+// literals and a terminating 0 (e.g. "x1 2 -3 0" or "x 1 2 -3 0") and state
+// that the exclusive disjunction of their literals is true.
+//
+// ReadBuilder detects builders implementing XorBuilder and passes them the xor
+// clause lines. Other builders see xor clause lines as (malformed) clause
+// lines.
+type XorBuilder interface {
+	Builder
+
+	// XorClause processes the literals of an xor clause line. As for the
+	// clauses, tmpClause is a shared buffer that should only be read from
+	// without being retained.
+	XorClause(tmpClause []int) error
+}
+
+// XorCNFFormula is a CNF formula extended with xor clauses. The exclusive
+// disjunction of the literals of each xor clause must be true (e.g. [1 -2]
+// means that variables 1 and 2 have the same value).
+//
+// The methods promoted from CNFFormula only consider the regular clauses,
+// except for WriteTo, String and the JSON methods, which XorCNFFormula
+// redefines to include the xor clauses.
+type XorCNFFormula struct {
+	CNFFormula
+	XorClauses [][]int
+}
+
+// ReadXorCNF is like ReadCNF but also accepts xor clause lines (see
+// XorBuilder), which are returned separately from the regular clauses. Xor
+// clauses count towards the number of clauses declared in the problem line.
+func ReadXorCNF(r io.Reader) (XorCNFFormula, error) {
+	b := xorCNFBuilder{cnfBuilder: cnfBuilder{opts: ReadCNFOptions{IgnoreClauseCount: true}}}
+	lines, err := readBuilder(context.Background(), r, &b, ReadOptions{})
+	if err != nil {
+		return XorCNFFormula{}, err
+	}
+	f, err := b.formula()
+	if err != nil {
+		return XorCNFFormula{}, newParseError(lines, "", err)
+	}
+	if got := len(f.Clauses) + len(b.xors); got < b.nClauses {
+		err := &ClauseCountError{Declared: b.nClauses, Actual: got}
+		return XorCNFFormula{}, newParseError(lines, "", err)
+	}
+	return XorCNFFormula{CNFFormula: f, XorClauses: b.xors}, nil
+}
+
+// xorCNFBuilder counts both regular and xor clauses against the declared
+// number of clauses, the embedded cnfBuilder ignoring that number.
+type xorCNFBuilder struct {
+	cnfBuilder
+	xors [][]int
+}
+
+func (b *xorCNFBuilder) Problem(p string, v int, c int) error {
+	if err := b.cnfBuilder.Problem(p, v, c); err != nil {
+		return err
+	}
+	b.xors = [][]int{}
+	return nil
+}
+
+func (b *xorCNFBuilder) Clause(tmp []int) error {
+	if err := b.checkCount(); err != nil {
+		return err
+	}
+	return b.cnfBuilder.Clause(tmp)
+}
+
+func (b *xorCNFBuilder) XorClause(tmp []int) error {
+	if b.cnf == nil {
+		return fmt.Errorf("xor %w", ErrClauseBeforeProblem)
+	}
+	if err := b.checkCount(); err != nil {
+		return err
+	}
+	b.xors = append(b.xors, append([]int{}, tmp...))
+	return nil
+}
+
+func (b *xorCNFBuilder) checkCount() error {
+	if b.cnf == nil {
+		return nil // reported by cnfBuilder
+	}
+	if s := len(b.cnf.Clauses) + len(b.xors); s == b.nClauses {
+		return &ClauseCountError{Declared: s, Actual: s + 1, TooMany: true}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package dimacs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadXorCNF(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		want    XorCNFFormula
+		wantErr bool
+	}{
+		{
+			desc:  "regular and xor clauses",
+			input: "c xor clauses\np cnf 3 4\n1 -2 0\nx1 2 -3 0\nx 2 3 0\n-1 0\n",
+			want: XorCNFFormula{
+				CNFFormula: CNFFormula{NumVars: 3, Clauses: [][]int{{1, -2}, {-1}}},
+				XorClauses: [][]int{{1, 2, -3}, {2, 3}},
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "no xor clauses",
+			input: validCNF_manyComments,
+			want: XorCNFFormula{
+				CNFFormula: testFormula,
+				XorClauses: [][]int{},
+			},
+			wantErr: false,
+		},
+		{
+			desc:    "xor clause before problem line",
+			input:   "x1 2 0\np cnf 2 1\n",
+			wantErr: true,
+		},
+		{
+			desc:    "too many clauses",
+			input:   "p cnf 3 2\n1 0\nx2 3 0\n-1 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "too many xor clauses",
+			input:   "p cnf 3 2\n1 0\nx2 3 0\nx1 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "missing clauses",
+			input:   "p cnf 3 3\n1 0\nx2 3 0\n",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid xor literal",
+			input:   "p cnf 3 1\nx1 y 0\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotErr := ReadXorCNF(strings.NewReader(tc.input))
+
+			if tc.wantErr && gotErr == nil {
+				t.Errorf("ReadXorCNF(): want error, got nil")
+			}
+			if !tc.wantErr && gotErr != nil {
+				t.Errorf("ReadXorCNF(): want no error, got %s", gotErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ReadXorCNF(): formula mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadCNF_xorClausesNotEnabled(t *testing.T) {
+	_, err := ReadCNF(strings.NewReader("p cnf 3 1\nx1 2 -3 0\n"))
+
+	if err == nil {
+		t.Errorf("ReadCNF(): want error, got nil")
+	}
+}
+
+func TestReadXorCNF_clauseBeforeProblem(t *testing.T) {
+	_, err := ReadXorCNF(strings.NewReader("x1 2 0\np cnf 2 1\n"))
+
+	if !errors.Is(err, ErrClauseBeforeProblem) {
+		t.Errorf("ReadXorCNF(): want error %q, got %v", ErrClauseBeforeProblem, err)
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Kind != KindClauseBeforeProblem {
+		t.Errorf("ReadXorCNF(): want error of kind %v, got %v", KindClauseBeforeProblem, err)
+	}
+}